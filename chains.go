@@ -0,0 +1,287 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// chainsFileNames are the file names looked up in the default chains directory, in order.
+var chainsFileNames = []string{"chains.yaml", "chains.yml", "chains.json"}
+
+// chainConfig is a single chain definition as written in a chains file.
+type chainConfig struct {
+	Name       string `json:"name" yaml:"name"`
+	Prefix     string `json:"prefix" yaml:"prefix"`
+	Encryption string `json:"encryption" yaml:"encryption"`
+	CoinType   uint32 `json:"coin_type" yaml:"coin_type"`
+}
+
+// chainsFile is the top level structure of a chains file.
+type chainsFile struct {
+	Chains []chainConfig `json:"chains" yaml:"chains"`
+}
+
+// defaultChainsFile returns the first chains file found in the user config directory
+// (e.g. ~/.config/vanity-forge/chains.yaml on Linux), or an empty string if there is none.
+func defaultChainsFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	for _, name := range chainsFileNames {
+		path := filepath.Join(dir, "vanity-forge", name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// loadAvailableChains merges the chains defined in path into AvailableChains.
+// If path is empty, the default chains file is used if there is one.
+func loadAvailableChains(path string) error {
+	if path == "" {
+		path = defaultChainsFile()
+	}
+	if path == "" {
+		return nil
+	}
+
+	userChains, err := loadChainsFile(path)
+	if err != nil {
+		return err
+	}
+
+	AvailableChains = mergeChains(AvailableChains, userChains)
+	return nil
+}
+
+// findChain returns the available chain with the given name.
+func findChain(name string) (chain, error) {
+	names := make([]string, len(AvailableChains))
+	for i, c := range AvailableChains {
+		if c.Name == name {
+			return c, nil
+		}
+		names[i] = c.Name
+	}
+
+	return chain{}, fmt.Errorf("Invalid chain. Must be one of the available chains: %v", names)
+}
+
+// loadChainsFile reads and validates the chain definitions in a YAML or JSON chains file.
+func loadChainsFile(path string) ([]chain, error) {
+	file, err := readChainsFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	chains, err := parseChainConfigs(file.Chains)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return chains, nil
+}
+
+// readChainsFile decodes a chains file without validating it.
+// Files with a .json extension are decoded as JSON, anything else as YAML.
+func readChainsFile(path string) (chainsFile, error) {
+	var file chainsFile
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return file, err
+	}
+
+	if isJSONFile(path) {
+		err = json.Unmarshal(data, &file)
+	} else {
+		err = yaml.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return file, fmt.Errorf("%s: %w", path, err)
+	}
+	return file, nil
+}
+
+// writeChainsFile encodes a chains file to path, as JSON for a .json extension and as YAML otherwise.
+func writeChainsFile(path string, file chainsFile) error {
+	data, err := encodeChainsFile(file, isJSONFile(path))
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// encodeChainsFile encodes a chains file as JSON or YAML.
+func encodeChainsFile(file chainsFile, asJSON bool) ([]byte, error) {
+	if asJSON {
+		data, err := json.MarshalIndent(file, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(file); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), encoder.Close()
+}
+
+// isJSONFile reports whether path has a .json extension.
+func isJSONFile(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}
+
+// parseChainConfigs converts chain definitions into chains and validates them.
+// All validation errors are joined into the returned error.
+func parseChainConfigs(configs []chainConfig) ([]chain, error) {
+	var errs []error
+	chains := make([]chain, 0, len(configs))
+	seen := make(map[string]bool, len(configs))
+
+	for i, cfg := range configs {
+		c, err := cfg.toChain()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("chain %d (%s): %w", i+1, cfg.Name, err))
+			continue
+		}
+		if seen[c.Name] {
+			errs = append(errs, fmt.Errorf("chain %d (%s): duplicate chain name", i+1, cfg.Name))
+			continue
+		}
+		seen[c.Name] = true
+		chains = append(chains, c)
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return chains, nil
+}
+
+// toChain converts the chain definition into a chain and validates it against its generator.
+func (cfg chainConfig) toChain() (chain, error) {
+	if cfg.Name == "" {
+		return chain{}, errors.New("name is required")
+	}
+
+	encryption, err := ParseEncryption(cfg.Encryption)
+	if err != nil {
+		return chain{}, err
+	}
+
+	c := chain{
+		Name:       cfg.Name,
+		Prefix:     cfg.Prefix,
+		CoinType:   cfg.CoinType,
+		Encryption: encryption,
+	}
+
+	switch encryption {
+	case ECSDA:
+		if c.Prefix == "" {
+			c.Prefix = "0x"
+		}
+		if c.Prefix != "0x" {
+			return chain{}, fmt.Errorf("prefix must be 0x for %s chains", encryption)
+		}
+		c.PrefixFull = c.Prefix
+	default:
+		if c.Prefix == "" {
+			return chain{}, errors.New("prefix is required")
+		}
+		if !validBech32Prefix(c.Prefix) {
+			return chain{}, fmt.Errorf("invalid bech32 prefix %q", c.Prefix)
+		}
+		c.PrefixFull = c.Prefix + "1"
+	}
+
+	return c, validateChain(c)
+}
+
+// validBech32Prefix checks if a string is a valid lowercase bech32 human readable part.
+func validBech32Prefix(prefix string) bool {
+	if len(prefix) == 0 || len(prefix) > 83 || prefix != strings.ToLower(prefix) {
+		return false
+	}
+	for _, char := range prefix {
+		if char < 33 || char > 126 {
+			return false
+		}
+	}
+	return true
+}
+
+// validateChain checks that a generator is registered for the chain and that the addresses
+// it generates have the chain's full prefix and the generator's address length.
+func validateChain(c chain) error {
+	g, err := NewGenerator(c)
+	if err != nil {
+		return err
+	}
+
+	address := g.GenerateWallet().Address
+	if !strings.HasPrefix(address, c.PrefixFull) {
+		return fmt.Errorf("generated address %s doesn't start with %s", address, c.PrefixFull)
+	}
+	if body := strings.TrimPrefix(address, c.PrefixFull); len(body) != g.AddressLength() {
+		return fmt.Errorf("generated addresses have %d characters after the prefix instead of %d", len(body), g.AddressLength())
+	}
+	return nil
+}
+
+// mergeChains merges extra chains into base. Chains in extra replace chains in base
+// with the same name, and new chains are appended in order.
+func mergeChains(base []chain, extra []chain) []chain {
+	merged := make([]chain, len(base), len(base)+len(extra))
+	copy(merged, base)
+
+	for _, c := range extra {
+		replaced := false
+		for i := range merged {
+			if merged[i].Name == c.Name {
+				merged[i] = c
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, c)
+		}
+	}
+	return merged
+}
+
+// mergeChainConfigs merges extra chain definitions into base, the same way mergeChains does.
+func mergeChainConfigs(base []chainConfig, extra []chainConfig) []chainConfig {
+	merged := make([]chainConfig, len(base), len(base)+len(extra))
+	copy(merged, base)
+
+	for _, cfg := range extra {
+		replaced := false
+		for i := range merged {
+			if merged[i].Name == cfg.Name {
+				merged[i] = cfg
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, cfg)
+		}
+	}
+	return merged
+}
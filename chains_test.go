@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTestChainsFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadChainsFile_YAML(t *testing.T) {
+	path := writeTestChainsFile(t, "chains.yaml", `
+chains:
+  - name: osmosis
+    prefix: osmo
+    encryption: secp256k1
+    coin_type: 118
+  - name: monad
+    encryption: ecsda
+    coin_type: 60
+`)
+
+	chains, err := loadChainsFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []chain{
+		{Name: "osmosis", Prefix: "osmo", PrefixFull: "osmo1", CoinType: 118, Encryption: Secp256k1},
+		{Name: "monad", Prefix: "0x", PrefixFull: "0x", CoinType: 60, Encryption: ECSDA},
+	}, chains)
+}
+
+func TestLoadChainsFile_JSON(t *testing.T) {
+	path := writeTestChainsFile(t, "chains.json", `{"chains": [{"name": "neutron", "prefix": "neutron", "encryption": "Secp256k1", "coin_type": 118}]}`)
+
+	chains, err := loadChainsFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []chain{
+		{Name: "neutron", Prefix: "neutron", PrefixFull: "neutron1", CoinType: 118, Encryption: Secp256k1},
+	}, chains)
+}
+
+func TestLoadChainsFile_Invalid(t *testing.T) {
+	path := writeTestChainsFile(t, "chains.yaml", `
+chains:
+  - name: ""
+    prefix: osmo
+    encryption: secp256k1
+  - name: bad-encryption
+    prefix: osmo
+    encryption: ed25519
+  - name: bad-prefix
+    prefix: "Os Mo"
+    encryption: secp256k1
+  - name: bad-evm-prefix
+    prefix: osmo
+    encryption: ecsda
+  - name: osmosis
+    prefix: osmo
+    encryption: secp256k1
+  - name: osmosis
+    prefix: osmo
+    encryption: secp256k1
+`)
+
+	_, err := loadChainsFile(path)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "name is required")
+	assert.Contains(t, err.Error(), "unknown encryption")
+	assert.Contains(t, err.Error(), "invalid bech32 prefix")
+	assert.Contains(t, err.Error(), "prefix must be 0x")
+	assert.Contains(t, err.Error(), "duplicate chain name")
+}
+
+func TestMergeChains(t *testing.T) {
+	base := []chain{
+		{Name: "cosmos", Prefix: "cosmos", PrefixFull: "cosmos1", CoinType: 118, Encryption: Secp256k1},
+		{Name: "berachain", Prefix: "0x", PrefixFull: "0x", CoinType: 60, Encryption: ECSDA},
+	}
+	extra := []chain{
+		{Name: "osmosis", Prefix: "osmo", PrefixFull: "osmo1", CoinType: 118, Encryption: Secp256k1},
+		{Name: "cosmos", Prefix: "cosmos", PrefixFull: "cosmos1", CoinType: 1, Encryption: Secp256k1},
+	}
+
+	merged := mergeChains(base, extra)
+	assert.Equal(t, []chain{
+		{Name: "cosmos", Prefix: "cosmos", PrefixFull: "cosmos1", CoinType: 1, Encryption: Secp256k1},
+		{Name: "berachain", Prefix: "0x", PrefixFull: "0x", CoinType: 60, Encryption: ECSDA},
+		{Name: "osmosis", Prefix: "osmo", PrefixFull: "osmo1", CoinType: 118, Encryption: Secp256k1},
+	}, merged)
+	assert.Equal(t, uint32(118), base[0].CoinType)
+}
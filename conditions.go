@@ -0,0 +1,128 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ConditionModes are the modes of conditions that can be combined.
+var ConditionModes = []string{"starts-with", "ends-with", "contains", "not-contains"}
+
+// conditionsMode is the matcher mode reported for results of combined conditions.
+const conditionsMode = "conditions"
+
+// condition is one of several conditions an address has to satisfy together.
+type condition struct {
+	Mode   string // one of ConditionModes
+	Search string // search string
+}
+
+// String returns the condition as "<mode> <search string>".
+func (c condition) String() string {
+	return c.Mode + " " + c.Search
+}
+
+// describeConditions returns the conditions as text, e.g. "starts-with team and ends-with 99".
+func describeConditions(conditions []condition) string {
+	parts := make([]string, len(conditions))
+	for i, c := range conditions {
+		parts[i] = c.String()
+	}
+	return strings.Join(parts, " and ")
+}
+
+// compileConditions compiles conditions into a single predicate, which matches if all of them hold.
+func compileConditions(conditions []condition) predicate {
+	var prefixes, suffixes, contains, excludes []string
+	for _, c := range conditions {
+		switch c.Mode {
+		case "starts-with":
+			prefixes = append(prefixes, c.Search)
+		case "ends-with":
+			suffixes = append(suffixes, c.Search)
+		case "contains":
+			contains = append(contains, c.Search)
+		case "not-contains":
+			excludes = append(excludes, c.Search)
+		}
+	}
+
+	return func(candidate string) bool {
+		for _, prefix := range prefixes {
+			if !strings.HasPrefix(candidate, prefix) {
+				return false
+			}
+		}
+		for _, suffix := range suffixes {
+			if !strings.HasSuffix(candidate, suffix) {
+				return false
+			}
+		}
+		for _, s := range contains {
+			if !strings.Contains(candidate, s) {
+				return false
+			}
+		}
+		for _, s := range excludes {
+			if strings.Contains(candidate, s) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// validateConditions checks that the conditions can hold together in an address of the given length.
+// It returns a slice of validation error messages for empty and contradictory conditions.
+func validateConditions(conditions []condition, length int) []string {
+	var errs []string
+	var prefix, suffix string
+	var required []condition
+
+	for _, c := range conditions {
+		if c.Search == "" {
+			errs = append(errs, "ERROR: Condition "+c.Mode+" can't be empty.")
+			continue
+		}
+
+		switch c.Mode {
+		case "starts-with":
+			if !strings.HasPrefix(prefix, c.Search) && !strings.HasPrefix(c.Search, prefix) {
+				errs = append(errs, "ERROR: starts-with "+prefix+" and starts-with "+c.Search+" contradict each other.")
+			} else if len(c.Search) > len(prefix) {
+				prefix = c.Search
+			}
+		case "ends-with":
+			if !strings.HasSuffix(suffix, c.Search) && !strings.HasSuffix(c.Search, suffix) {
+				errs = append(errs, "ERROR: ends-with "+suffix+" and ends-with "+c.Search+" contradict each other.")
+			} else if len(c.Search) > len(suffix) {
+				suffix = c.Search
+			}
+		}
+
+		if c.Mode != "not-contains" {
+			required = append(required, c)
+		}
+	}
+
+	// The prefix and suffix overlap if they are longer than the address together
+	if offset := length - len(suffix); len(prefix) > offset && offset >= 0 && len(prefix) <= length {
+		if prefix[offset:] != suffix[:len(prefix)-offset] {
+			errs = append(errs, "ERROR: starts-with "+prefix+" and ends-with "+suffix+" overlap in addresses of "+
+				strconv.Itoa(length)+" characters and contradict each other.")
+		}
+	}
+
+	for _, c := range conditions {
+		if c.Mode != "not-contains" || c.Search == "" {
+			continue
+		}
+		for _, r := range required {
+			if strings.Contains(r.Search, c.Search) {
+				errs = append(errs, "ERROR: not-contains "+c.Search+" contradicts "+r.String()+".")
+			}
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompileConditions(t *testing.T) {
+	match := compileConditions([]condition{
+		{Mode: "starts-with", Search: "ac"},
+		{Mode: "ends-with", Search: "99"},
+		{Mode: "contains", Search: "de"},
+		{Mode: "not-contains", Search: "xx"},
+	})
+
+	assert.True(t, match("acde99"))
+	assert.True(t, match("ac0de0099"))
+	assert.False(t, match("acde9"))
+	assert.False(t, match("xacde99"))
+	assert.False(t, match("ac99"))
+	assert.False(t, match("acdexx99"))
+}
+
+func TestValidateConditions(t *testing.T) {
+	assert.Empty(t, validateConditions([]condition{
+		{Mode: "starts-with", Search: "ac"},
+		{Mode: "starts-with", Search: "acde"},
+		{Mode: "ends-with", Search: "99"},
+		{Mode: "not-contains", Search: "xx"},
+	}, 38))
+
+	assert.Equal(t, []string{
+		"ERROR: starts-with ac and starts-with de contradict each other.",
+	}, validateConditions([]condition{{Mode: "starts-with", Search: "ac"}, {Mode: "starts-with", Search: "de"}}, 38))
+
+	assert.Equal(t, []string{
+		"ERROR: ends-with 99 and ends-with 98 contradict each other.",
+	}, validateConditions([]condition{{Mode: "ends-with", Search: "99"}, {Mode: "ends-with", Search: "98"}}, 38))
+
+	// In a 4 character address the prefix and suffix share the middle characters
+	assert.Empty(t, validateConditions([]condition{{Mode: "starts-with", Search: "acd"}, {Mode: "ends-with", Search: "cde"}}, 4))
+	assert.Equal(t, []string{
+		"ERROR: starts-with acd and ends-with xde overlap in addresses of 4 characters and contradict each other.",
+	}, validateConditions([]condition{{Mode: "starts-with", Search: "acd"}, {Mode: "ends-with", Search: "xde"}}, 4))
+
+	assert.Equal(t, []string{
+		"ERROR: not-contains cd contradicts starts-with acde.",
+	}, validateConditions([]condition{{Mode: "starts-with", Search: "acde"}, {Mode: "not-contains", Search: "cd"}}, 38))
+
+	assert.Equal(t, []string{"ERROR: Condition contains can't be empty."},
+		validateConditions([]condition{{Mode: "contains", Search: ""}}, 38))
+}
+
+func TestMatcher_Conditions(t *testing.T) {
+	cosmos := AvailableChains[1]
+	m := matcher{Chain: cosmos, Conditions: []condition{
+		{Mode: "starts-with", Search: "acde"},
+		{Mode: "ends-with", Search: "qq"},
+		{Mode: "not-contains", Search: "zz"},
+	}}
+
+	assert.Empty(t, m.ValidateInput())
+	assert.True(t, m.Match("cosmos1acdexxqq"))
+	assert.False(t, m.Match("cosmos1acdezzqq"))
+	assert.Equal(t, []pattern{{Mode: conditionsMode, Search: "starts-with acde and ends-with qq and not-contains zz"}}, m.patterns())
+
+	// Closeness adds up the characters of the starts-with and ends-with conditions
+	assert.Equal(t, 4, m.Closeness("cosmos1acdxxxq"))
+	assert.Equal(t, 6, m.searchLength())
+
+	d, err := m.Difficulty()
+	assert.NoError(t, err)
+	assert.InDelta(t, math.Pow(32, -6)*math.Pow(1-math.Pow(32, -2), 37), d.Probability, 1e-15)
+
+	m.Conditions = append(m.Conditions, condition{Mode: "contains", Search: "bio"})
+	errs := m.ValidateInput()
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0], "bio contains bech32 incompatible characters.")
+}
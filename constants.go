@@ -1,5 +1,10 @@
 package main
 
+import (
+	"fmt"
+	"strings"
+)
+
 type Encryption int64
 
 const (
@@ -9,10 +14,39 @@ const (
 	ECSDA
 )
 
+// String returns the name of the encryption.
+func (e Encryption) String() string {
+	switch e {
+	case Secp256k1:
+		return "Secp256k1"
+	case Ethsecp256k1:
+		return "Ethsecp256k1"
+	case ECSDA:
+		return "ECSDA"
+	default:
+		return "Undefined"
+	}
+}
+
+// ParseEncryption parses an encryption name as used in chain files, ignoring case.
+func ParseEncryption(s string) (Encryption, error) {
+	switch strings.ToLower(s) {
+	case "secp256k1":
+		return Secp256k1, nil
+	case "ethsecp256k1":
+		return Ethsecp256k1, nil
+	case "ecsda", "ecdsa":
+		return ECSDA, nil
+	default:
+		return Undefined, fmt.Errorf("unknown encryption %q, must be one of: secp256k1, ethsecp256k1, ecsda", s)
+	}
+}
+
 type chain struct {
 	Name       string
 	Prefix     string
 	PrefixFull string
+	CoinType   uint32 // BIP44 coin type
 	Encryption
 }
 
@@ -25,16 +59,23 @@ type settings struct {
 	RequiredDigits  int    // number of digits to generate
 }
 
-type walletgenerator struct {
-	GenerateWallet func() wallet
-}
-
 type matcher struct {
 	Mode            string
 	SearchString    string
 	Chain           chain
 	RequiredLetters int
 	RequiredDigits  int
+	CaseSensitive   bool        // match the EIP-55 checksum case of mixed case addresses instead of lowercasing them
+	Patterns        []pattern   // searches of a patterns file, used instead of Mode and SearchString when set
+	Conditions      []condition // conditions that must all hold, used instead of Mode and SearchString when set
+	Words           []string    // wordlist of the words mode
+	Leetspeak       bool        // match words with leetspeak substitutions for letters addresses can't contain
+	Mask            mask        // character classes per position of the address
+	Constraints     []countConstraint
+	Generator       Generator // resolved generator, looked up from Chain when nil
+	predicate       predicate // compiled search, compiled from Mode and SearchString when nil
+	patternSet      *patternSet
+	wordSet         *wordSet // filtered wordlist, built from Words when nil
 }
 
 var (
@@ -43,26 +84,31 @@ var (
 			Name:       "celestia",
 			Prefix:     "celestia",
 			PrefixFull: "celestia1",
+			CoinType:   118,
 			Encryption: Secp256k1,
 		},
 		{
 			Name:       "cosmos",
 			Prefix:     "cosmos",
 			PrefixFull: "cosmos1",
+			CoinType:   118,
 			Encryption: Secp256k1,
 		},
 		{
 			Name:       "dydx",
 			Prefix:     "dydx",
 			PrefixFull: "dydx1",
+			CoinType:   118,
 			Encryption: Secp256k1,
 		},
 		{
 			Name:       "berachain",
 			Prefix:     "0x",
 			PrefixFull: "0x",
+			CoinType:   60,
 			Encryption: ECSDA,
 		},
 	}
-	MatcherModes = []string{"contains", "starts-with", "ends-with", "regex"}
+	MatcherModes = []string{"contains", "starts-with", "ends-with", "regex", "leading-zero-bytes", "zero-nibbles", "words", "runs", "repeats", "palindromes", "sequences"}
+	CaseModes    = []string{"insensitive", "checksum"}
 )
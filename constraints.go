@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// countConstraint limits how many digits or letters an address, or a region of it, contains.
+// It is written as "<class><op><count>[@<start>:<end>]", e.g. "letters<=10" or "digits=8@-8:".
+type countConstraint struct {
+	Class string // digits or letters
+	Op    string // =, <= or >=
+	Count int
+	Start int // start of the region, negative counts from the end
+	End   int // end of the region, negative counts from the end and 0 is the end of the address
+}
+
+// constraintOps are the comparison operators of count constraints, longest first for parsing.
+var constraintOps = []string{"<=", ">=", "="}
+
+// parseConstraint parses a count constraint like "digits>=4" or "letters=0@0:6".
+func parseConstraint(s string) (countConstraint, error) {
+	constraint, region, hasRegion := strings.Cut(s, "@")
+
+	var c countConstraint
+	var count string
+	for _, op := range constraintOps {
+		if class, n, ok := strings.Cut(constraint, op); ok {
+			c.Class, c.Op, count = class, op, n
+			break
+		}
+	}
+	if c.Op == "" {
+		return c, fmt.Errorf("expected <digits|letters><op><count>[@<start>:<end>] with op =, <= or >=, got %q", s)
+	}
+
+	if c.Class != "digits" && c.Class != "letters" {
+		return c, fmt.Errorf("invalid character class %q, must be digits or letters", c.Class)
+	}
+
+	var err error
+	if c.Count, err = strconv.Atoi(count); err != nil || c.Count < 0 {
+		return c, fmt.Errorf("invalid count %q, must be a number", count)
+	}
+
+	if hasRegion {
+		start, end, ok := strings.Cut(region, ":")
+		if !ok {
+			return c, fmt.Errorf("invalid region %q, expected <start>:<end>", region)
+		}
+		if c.Start, err = parseRegionIndex(start); err != nil {
+			return c, err
+		}
+		if c.End, err = parseRegionIndex(end); err != nil {
+			return c, err
+		}
+	}
+
+	return c, nil
+}
+
+// parseRegionIndex parses the start or end of a region, an empty index is 0.
+func parseRegionIndex(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid region index %q, must be a number", s)
+	}
+	return i, nil
+}
+
+// String returns the constraint in the form parsed by parseConstraint.
+func (c countConstraint) String() string {
+	s := c.Class + c.Op + strconv.Itoa(c.Count)
+	if c.Start != 0 || c.End != 0 {
+		s += "@" + strconv.Itoa(c.Start) + ":"
+		if c.End != 0 {
+			s += strconv.Itoa(c.End)
+		}
+	}
+	return s
+}
+
+// region returns the start and end of the region in an address of the given length.
+func (c countConstraint) region(length int) (int, int) {
+	start, end := c.Start, c.End
+	if start < 0 {
+		start += length
+	}
+	if end <= 0 {
+		end += length
+	}
+	return start, end
+}
+
+// Check reports whether the candidate satisfies the constraint. The characters of the class
+// are given by chars.
+func (c countConstraint) Check(candidate string, chars string) bool {
+	start, end := c.region(len(candidate))
+	if start < 0 || end > len(candidate) || start > end {
+		return false
+	}
+
+	n := 0
+	for _, char := range candidate[start:end] {
+		if strings.ContainsRune(chars, char) {
+			n++
+		}
+	}
+	return c.holds(n)
+}
+
+// holds reports whether a count of n characters satisfies the constraint.
+func (c countConstraint) holds(n int) bool {
+	switch c.Op {
+	case "<=":
+		return n <= c.Count
+	case ">=":
+		return n >= c.Count
+	default:
+		return n == c.Count
+	}
+}
+
+// validate checks the constraint against addresses of the given length.
+// It returns a slice of validation error messages.
+func (c countConstraint) validate(length int) []string {
+	start, end := c.region(length)
+	if start < 0 || end > length || start >= end {
+		return []string{"ERROR: Constraint " + c.String() + " has a region outside of addresses of " + strconv.Itoa(length) + " characters."}
+	}
+	if c.Op != "<=" && c.Count > end-start {
+		return []string{"ERROR: Constraint " + c.String() + " needs more characters than its region of " + strconv.Itoa(end-start) + " characters has."}
+	}
+	return nil
+}
+
+// mask is a mask of character classes per position of an address: ? is any character,
+// d a digit and a a letter. The head is matched at the start of the address and the tail
+// at its end. A mask without * has no tail, e.g. "????dddd", "aaaa*" or "*dddd".
+type mask struct {
+	Head string
+	Tail string
+}
+
+// parseMask parses a mask of the characters ?, d and a with at most one *.
+func parseMask(s string) (mask, error) {
+	if strings.Trim(s, "?da*") != "" || strings.Count(s, "*") > 1 {
+		return mask{}, fmt.Errorf("invalid mask %q, must consist of ?, d, a and at most one *", s)
+	}
+
+	head, tail, _ := strings.Cut(s, "*")
+	return mask{Head: head, Tail: tail}, nil
+}
+
+// String returns the mask in the form parsed by parseMask.
+func (mk mask) String() string {
+	if mk.Tail != "" {
+		return mk.Head + "*" + mk.Tail
+	}
+	return mk.Head
+}
+
+// Empty reports whether the mask matches every address.
+func (mk mask) Empty() bool {
+	return strings.Trim(mk.Head+mk.Tail, "?") == ""
+}
+
+// Match reports whether the candidate matches the mask, with the digits and letters of its alphabet.
+func (mk mask) Match(candidate string, digits string, letters string) bool {
+	if len(mk.Head)+len(mk.Tail) > len(candidate) {
+		return false
+	}
+	return matchMask(mk.Head, candidate, digits, letters) &&
+		matchMask(mk.Tail, candidate[len(candidate)-len(mk.Tail):], digits, letters)
+}
+
+// matchMask reports whether the start of the candidate matches the classes.
+func matchMask(classes string, candidate string, digits string, letters string) bool {
+	for i := 0; i < len(classes); i++ {
+		switch classes[i] {
+		case 'd':
+			if !strings.ContainsRune(digits, rune(candidate[i])) {
+				return false
+			}
+		case 'a':
+			if !strings.ContainsRune(letters, rune(candidate[i])) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// positions returns the number of digit and letter positions of the mask.
+func (mk mask) positions() (digits int, letters int) {
+	classes := mk.Head + mk.Tail
+	return strings.Count(classes, "d"), strings.Count(classes, "a")
+}
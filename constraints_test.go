@@ -0,0 +1,99 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConstraint(t *testing.T) {
+	c, err := parseConstraint("digits=8@-8:")
+	assert.NoError(t, err)
+	assert.Equal(t, countConstraint{Class: "digits", Op: "=", Count: 8, Start: -8}, c)
+	assert.Equal(t, "digits=8@-8:", c.String())
+
+	c, err = parseConstraint("letters<=10")
+	assert.NoError(t, err)
+	assert.Equal(t, countConstraint{Class: "letters", Op: "<=", Count: 10}, c)
+
+	c, err = parseConstraint("letters>=2@0:6")
+	assert.NoError(t, err)
+	assert.Equal(t, countConstraint{Class: "letters", Op: ">=", Count: 2, Start: 0, End: 6}, c)
+	assert.Equal(t, "letters>=2@0:6", c.String())
+
+	_, err = parseConstraint("digits")
+	assert.ErrorContains(t, err, "expected <digits|letters><op><count>")
+
+	_, err = parseConstraint("vowels=2")
+	assert.ErrorContains(t, err, `invalid character class "vowels"`)
+
+	_, err = parseConstraint("digits=x")
+	assert.ErrorContains(t, err, `invalid count "x"`)
+
+	_, err = parseConstraint("digits=2@4")
+	assert.ErrorContains(t, err, `invalid region "4"`)
+}
+
+func TestCountConstraint_Check(t *testing.T) {
+	last4Digits := countConstraint{Class: "digits", Op: "=", Count: 4, Start: -4}
+	assert.True(t, last4Digits.Check("abcd1234", bech16digits))
+	assert.False(t, last4Digits.Check("abcd123f", bech16digits))
+
+	maxLetters := countConstraint{Class: "letters", Op: "<=", Count: 2}
+	assert.True(t, maxLetters.Check("a1b2", bech16letters))
+	assert.False(t, maxLetters.Check("a1bc", bech16letters))
+
+	region := countConstraint{Class: "letters", Op: ">=", Count: 2, Start: 1, End: -1}
+	assert.True(t, region.Check("1ab1", bech16letters))
+	assert.False(t, region.Check("a1b1", bech16letters))
+
+	assert.Empty(t, region.validate(4))
+	assert.NotEmpty(t, countConstraint{Class: "digits", Op: "=", Count: 1, Start: 4}.validate(4))
+	assert.NotEmpty(t, countConstraint{Class: "digits", Op: ">=", Count: 5, Start: -4}.validate(40))
+}
+
+func TestMask(t *testing.T) {
+	m, err := parseMask("aa*dd")
+	assert.NoError(t, err)
+	assert.Equal(t, mask{Head: "aa", Tail: "dd"}, m)
+	assert.Equal(t, "aa*dd", m.String())
+
+	assert.True(t, m.Match("ab0c12", bech16digits, bech16letters))
+	assert.False(t, m.Match("a10c12", bech16digits, bech16letters))
+	assert.False(t, m.Match("ab0c1f", bech16digits, bech16letters))
+	assert.False(t, m.Match("ab1", bech16digits, bech16letters))
+
+	m, err = parseMask("????dddd")
+	assert.NoError(t, err)
+	assert.True(t, m.Match("abcd1234ef", bech16digits, bech16letters))
+	assert.False(t, m.Match("abcd123ef", bech16digits, bech16letters))
+
+	_, err = parseMask("dd*d*")
+	assert.Error(t, err)
+	_, err = parseMask("ddx")
+	assert.Error(t, err)
+}
+
+func TestMatcher_Constraints(t *testing.T) {
+	berachain := AvailableChains[3]
+	m := matcher{Mode: "starts-with", SearchString: "a", Chain: berachain,
+		Mask:        mask{Tail: "dd"},
+		Constraints: []countConstraint{{Class: "letters", Op: "<=", Count: 3}},
+	}
+
+	assert.Empty(t, m.ValidateInput())
+	assert.True(t, m.Match("0xaB123456"))
+	assert.False(t, m.Match("0xaB1234f6"))
+	assert.False(t, m.Match("0xaBcD3456"))
+
+	// The last two characters are digits with probability 10/16 each
+	d, err := m.Difficulty()
+	assert.NoError(t, err)
+	withoutConstraints, err := matcher{Mode: "starts-with", SearchString: "a", Chain: berachain}.Difficulty()
+	assert.NoError(t, err)
+	assert.Less(t, d.Probability, withoutConstraints.Probability*math.Pow(10.0/16, 2))
+
+	m.Mask = mask{Head: "????????????????????????????????????????d"}
+	assert.Equal(t, []string{"ERROR: Mask ????????????????????????????????????????d is longer than addresses of 40 characters."}, m.ValidateInput())
+}
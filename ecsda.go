@@ -3,6 +3,7 @@ package main
 import (
 	"crypto/ecdsa"
 	"log"
+	"strconv"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/crypto"
@@ -12,6 +13,12 @@ type ecsdaWallet struct {
 	Chain chain
 }
 
+func init() {
+	RegisterGenerator(ECSDA, func(c chain) Generator {
+		return ecsdaWallet{Chain: c}
+	})
+}
+
 // On Ethereum and other networks compatible with the Ethereum Virtual Machine (EVM), public addresses all share the same format: they begin with 0x, and are followed by 40 alphanumeric characters (numerals and letters), adding up to 42 characters in total. They're also not case sensitive.
 
 // This address is a number, even though it also includes alphabetical characters. This is because the hexadecimal (base 16) system used to generate the address doesn't just use numerals, like our ten-digit decimal system. Instead, the hexadecimal system uses the numerals 0-9 and the letters A-F. This means it has 16 characters at its disposal, hence the name base 16. In computer science and many programming languages, the 0x prefix is used at the start of all hex numbers, as they are known, to differentiate them from decimal values.
@@ -25,6 +32,29 @@ const bech16letters = "abcdefABCDEF"
 // bech16chars is a constant string that represents the characters used in the bech16 encoding scheme, which includes both digits and letters.
 const bech16chars = bech16digits + bech16letters
 
+// hexAddressLength is the length of a hex encoded 20 byte address after the 0x prefix.
+const hexAddressLength = 40
+
+// Alphabet returns the characters used in the bech16 encoding scheme.
+func (w ecsdaWallet) Alphabet() string {
+	return bech16chars
+}
+
+// Digits returns the digits used in the bech16 encoding scheme.
+func (w ecsdaWallet) Digits() string {
+	return bech16digits
+}
+
+// Letters returns the letters used in the bech16 encoding scheme.
+func (w ecsdaWallet) Letters() string {
+	return bech16letters
+}
+
+// AddressLength returns the length of the address after the 0x prefix.
+func (w ecsdaWallet) AddressLength() int {
+	return hexAddressLength
+}
+
 // bech16Only checks if the given string contains only characters from the bech16 character set.
 func (w ecsdaWallet) bech16Only(s string) bool {
 	return w.countUnionChars(s, bech16chars) == len(s)
@@ -70,17 +100,18 @@ func (w ecsdaWallet) CheckRequiredLetters(candidate string, required int) bool {
 // It returns a slice of error messages indicating the validation errors, if any.
 func (w ecsdaWallet) ValidateInput(SearchString string, RequiredLetters int, RequiredDigits int) []string {
 	var errs []string
+	maxLength := w.AddressLength()
 	if !w.bech16Only(SearchString) {
 		errs = append(errs, "ERROR: "+SearchString+" contains bech16 incompatible characters")
 	}
-	if len(SearchString) > 40 {
-		errs = append(errs, "ERROR: "+SearchString+" is too long. Must be max 40 characters.")
+	if len(SearchString) > maxLength {
+		errs = append(errs, "ERROR: "+SearchString+" is too long. Must be max "+strconv.Itoa(maxLength)+" characters.")
 	}
 	if RequiredDigits < 0 || RequiredLetters < 0 {
 		errs = append(errs, "ERROR: Can't require negative amount of characters.")
 	}
-	if RequiredDigits+RequiredLetters > 40 {
-		errs = append(errs, "ERROR: Can't require more than 40 characters.")
+	if RequiredDigits+RequiredLetters > maxLength {
+		errs = append(errs, "ERROR: Can't require more than "+strconv.Itoa(maxLength)+" characters.")
 	}
 	return errs
 }
@@ -93,6 +124,34 @@ func (w ecsdaWallet) GenerateWallet() wallet {
 		log.Fatal(err)
 	}
 
+	return w.walletFromECDSA(privateKey)
+}
+
+// WalletFromPrivateKey derives the wallet of a private key.
+// It returns an error if the bytes are not a valid secp256k1 private key.
+func (w ecsdaWallet) WalletFromPrivateKey(privateKey []byte) (wallet, error) {
+	privateKeyECDSA, err := crypto.ToECDSA(privateKey)
+	if err != nil {
+		return wallet{}, err
+	}
+
+	return w.walletFromECDSA(privateKeyECDSA), nil
+}
+
+// WalletFromPublicKey derives the wallet of a public key.
+// It returns a wallet struct containing the address and public key bytes.
+func (w ecsdaWallet) WalletFromPublicKey(publicKey []byte) (wallet, error) {
+	publicKeyECDSA, err := parsePublicKey(publicKey)
+	if err != nil {
+		return wallet{}, err
+	}
+
+	return w.walletFromPublicKeyECDSA(publicKeyECDSA), nil
+}
+
+// walletFromECDSA derives the public key and address of a private key.
+// It returns a wallet struct containing the address, public key, and private key bytes.
+func (w ecsdaWallet) walletFromECDSA(privateKey *ecdsa.PrivateKey) wallet {
 	privateKeyBytes := crypto.FromECDSA(privateKey)
 
 	publicKey := privateKey.Public()
@@ -101,9 +160,18 @@ func (w ecsdaWallet) GenerateWallet() wallet {
 		log.Fatal("cannot assert type: publicKey is not of type *ecdsa.PublicKey")
 	}
 
-	publicKeyBytes := crypto.FromECDSAPub(publicKeyECDSA)
+	derived := w.walletFromPublicKeyECDSA(publicKeyECDSA)
+	derived.PrivateKey = privateKeyBytes
+
+	return derived
+}
+
+// walletFromPublicKeyECDSA derives the address of a public key.
+// It returns a wallet struct containing the address and public key bytes.
+func (w ecsdaWallet) walletFromPublicKeyECDSA(publicKey *ecdsa.PublicKey) wallet {
+	publicKeyBytes := crypto.FromECDSAPub(publicKey)
 
-	address := crypto.PubkeyToAddress(*publicKeyECDSA).Hex()
+	address := crypto.PubkeyToAddress(*publicKey)
 
-	return wallet{address, publicKeyBytes, privateKeyBytes}
+	return wallet{Address: address.Hex(), AddressBytes: address.Bytes(), PublicKey: publicKeyBytes}
 }
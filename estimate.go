@@ -0,0 +1,495 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/spf13/pflag"
+	"golang.org/x/exp/slices"
+)
+
+// estimateQuantiles are the completion probabilities reported by estimates.
+var estimateQuantiles = []float64{0.5, 0.9, 0.99}
+
+// estimateBenchmarkAttempts is the number of expected attempts above which a search
+// is benchmarked before it starts, to warn about long searches.
+const estimateBenchmarkAttempts = 1e6
+
+// estimateWarningDuration is the expected search time above which a warning is shown.
+const estimateWarningDuration = 10 * time.Minute
+
+// errEstimateUnsupported is returned for matcher modes whose difficulty can't be estimated.
+var errEstimateUnsupported = errors.New("difficulty can't be estimated for this matcher mode")
+
+// difficulty is the expected difficulty of finding one matching address.
+type difficulty struct {
+	Probability float64 // probability that a single generated address matches
+	Rate        float64 // generated addresses per second, 0 if unknown
+}
+
+// ExpectedAttempts returns the expected number of generated addresses until one matches.
+func (d difficulty) ExpectedAttempts() float64 {
+	return 1 / d.Probability
+}
+
+// QuantileAttempts returns the number of generated addresses after which a match
+// has been found with probability q.
+func (d difficulty) QuantileAttempts(q float64) float64 {
+	if d.Probability >= 1 {
+		return 1
+	}
+	return math.Log1p(-q) / math.Log1p(-d.Probability)
+}
+
+// Seconds returns the time in seconds needed to generate the given number of addresses.
+func (d difficulty) Seconds(attempts float64) float64 {
+	return attempts / d.Rate
+}
+
+// String formats the difficulty, including expected times if the rate is known.
+func (d difficulty) String() string {
+	s := fmt.Sprintf("Probability per attempt: %.3g\n", d.Probability) +
+		fmt.Sprintf("Expected attempts: %s", formatCount(d.ExpectedAttempts()))
+
+	if d.Rate > 0 {
+		s += fmt.Sprintf("\nRate: %s keys/s\n", formatCount(d.Rate)) +
+			"Expected time: " + formatSeconds(d.Seconds(d.ExpectedAttempts()))
+		for _, q := range estimateQuantiles {
+			s += fmt.Sprintf("\n  %2.0f%% chance within %s", q*100, formatSeconds(d.Seconds(d.QuantileAttempts(q))))
+		}
+	}
+
+	return s
+}
+
+// searchWarning returns a warning if finding the given number of accounts is expected to take
+// longer than estimateWarningDuration or is impossible, and an empty string otherwise.
+func searchWarning(d difficulty, accounts int) string {
+	if d.Probability == 0 {
+		return "WARNING: No address can match the search criteria."
+	}
+
+	expected := d.Seconds(d.ExpectedAttempts()) * float64(accounts)
+	if d.Rate <= 0 || expected < estimateWarningDuration.Seconds() {
+		return ""
+	}
+
+	return fmt.Sprintf("WARNING: Finding %d matching accounts is expected to take %s at %s keys/s.",
+		accounts, formatSeconds(expected), formatCount(d.Rate))
+}
+
+// alphabetStats returns the number of distinct case-insensitive characters in the generator's
+// alphabet and how many of them are digits.
+func alphabetStats(g Generator) (size int, digits int) {
+	seen := make(map[rune]bool)
+	for _, char := range strings.ToLower(g.Alphabet()) {
+		if !seen[char] {
+			seen[char] = true
+			if strings.ContainsRune(g.Digits(), char) {
+				digits++
+			}
+		}
+	}
+	return len(seen), digits
+}
+
+// Difficulty estimates the probability that a single generated address matches the matcher,
+// assuming the characters of the address body are uniformly and independently distributed.
+// The mask and count constraints are treated as independent of the search.
+// It returns errEstimateUnsupported for matcher modes without a closed form estimate.
+func (m matcher) Difficulty() (difficulty, error) {
+	d, err := m.searchDifficulty()
+	if err != nil {
+		return d, err
+	}
+
+	g := m.mustGenerator()
+	size, digits := alphabetStats(g)
+	d.Probability *= m.constraintsProbability(float64(digits)/float64(size), g.AddressLength())
+	return d, nil
+}
+
+// searchDifficulty estimates the probability that a single generated address matches the search
+// of the matcher and its required letters and digits.
+func (m matcher) searchDifficulty() (difficulty, error) {
+	g, err := m.generator()
+	if err != nil {
+		return difficulty{}, err
+	}
+	if len(m.Patterns) > 0 {
+		return difficulty{}, errEstimateUnsupported
+	}
+
+	size, digits := alphabetStats(g)
+	length := g.AddressLength()
+
+	if slices.Contains(ZeroModes, m.Mode) {
+		return difficulty{Probability: zeroProbability(m.Mode, m.searchLength(), length/2)}, nil
+	}
+	if slices.Contains(StructureModes, m.Mode) {
+		// The required letters and digits are treated as independent of the shape
+		probability := structureProbability(m.Mode, m.searchLength(), m.alphabet(g), length) *
+			requiredCharsProbability(length, float64(digits)/float64(size), m.RequiredDigits, m.RequiredLetters)
+		return difficulty{Probability: probability}, nil
+	}
+	if m.Mode == wordsMode {
+		words, err := m.words()
+		if err != nil {
+			return difficulty{}, err
+		}
+		return difficulty{Probability: wordsProbability(words, size, length)}, nil
+	}
+
+	var probability float64
+	fixed := m.SearchString
+	if len(m.Conditions) > 0 {
+		probability, fixed = conditionsProbability(m.Conditions, size, length)
+	} else if probability, err = searchProbability(m.Mode, len(fixed), size, length); err != nil {
+		return difficulty{}, err
+	}
+	search := min(len(fixed), length)
+
+	// The characters of the search string are fixed, the required letters and digits
+	// have to be found in the remaining characters.
+	fixedDigits := 0
+	for _, char := range fixed[:search] {
+		if strings.ContainsRune(g.Digits(), char) {
+			fixedDigits++
+		}
+	}
+
+	// The checksum picks the case of every letter, so each cased letter halves the probability
+	if m.CaseSensitive {
+		probability *= math.Pow(2, -float64(search-fixedDigits))
+	}
+
+	probability *= requiredCharsProbability(
+		length-search,
+		float64(digits)/float64(size),
+		m.RequiredDigits-fixedDigits,
+		m.RequiredLetters-(search-fixedDigits),
+	)
+
+	return difficulty{Probability: probability}, nil
+}
+
+// searchProbability returns the probability that an address of the given length over an alphabet
+// of size characters matches a search string of search characters in the mode.
+func searchProbability(mode string, search int, size int, length int) (float64, error) {
+	switch mode {
+	case "starts-with", "ends-with":
+		return math.Pow(float64(size), -float64(search)), nil
+	case "contains", "":
+		positions := length - search + 1
+		if positions < 1 {
+			return 0, nil
+		}
+		return -math.Expm1(float64(positions) * math.Log1p(-math.Pow(float64(size), -float64(search)))), nil
+	default:
+		return 0, errEstimateUnsupported
+	}
+}
+
+// zeroProbability returns the probability that an address of the given number of bytes scores at
+// least minScore in a zero mode. Every byte is zero with probability 1/256, every nibble with 1/16.
+func zeroProbability(mode string, minScore int, bytes int) float64 {
+	if mode == "zero-nibbles" {
+		return requiredCharsProbability(bytes*2, 1.0/16, minScore, 0)
+	}
+	return math.Pow(256, -float64(minScore))
+}
+
+// wordsProbability returns the probability that an address contains any word of the set,
+// treating the words as independent.
+func wordsProbability(words *wordSet, size int, length int) float64 {
+	none := 0.0
+	for _, form := range words.forms {
+		p, _ := searchProbability("contains", len(form), size, length)
+		none += math.Log1p(-p)
+	}
+	return -math.Expm1(none)
+}
+
+// conditionsProbability returns the probability that an address matches all conditions, treating
+// them as independent, and the characters they fix. Of several starts-with or ends-with conditions
+// only the longest counts, as validateConditions made sure the shorter ones are part of it.
+func conditionsProbability(conditions []condition, size int, length int) (float64, string) {
+	var prefix, suffix string
+	for _, c := range conditions {
+		switch {
+		case c.Mode == "starts-with" && len(c.Search) > len(prefix):
+			prefix = c.Search
+		case c.Mode == "ends-with" && len(c.Search) > len(suffix):
+			suffix = c.Search
+		}
+	}
+
+	probability, _ := searchProbability("starts-with", len(prefix)+len(suffix), size, length)
+	fixed := prefix + suffix
+
+	for _, c := range conditions {
+		p, _ := searchProbability("contains", len(c.Search), size, length)
+		switch c.Mode {
+		case "contains":
+			probability *= p
+			fixed += c.Search
+		case "not-contains":
+			probability *= 1 - p
+		}
+	}
+
+	return probability, fixed
+}
+
+// constraintsProbability returns the probability that an address of the given length, with
+// characters that are digits with probability pd, matches the mask and the count constraints.
+func (m matcher) constraintsProbability(pd float64, length int) float64 {
+	digits, letters := m.Mask.positions()
+	probability := math.Pow(pd, float64(digits)) * math.Pow(1-pd, float64(letters))
+
+	for _, c := range m.Constraints {
+		p := pd
+		if c.Class == "letters" {
+			p = 1 - pd
+		}
+
+		start, end := c.region(length)
+		n := end - start
+		matching := 0.0
+		for k := 0; k <= n; k++ {
+			if c.holds(k) {
+				matching += binomial(n, k) * math.Pow(p, float64(k)) * math.Pow(1-p, float64(n-k))
+			}
+		}
+		probability *= matching
+	}
+
+	return probability
+}
+
+// requiredCharsProbability returns the probability that n characters, each a digit with
+// probability pd and a letter otherwise, contain at least minDigits digits and minLetters letters.
+func requiredCharsProbability(n int, pd float64, minDigits int, minLetters int) float64 {
+	minDigits = max(minDigits, 0)
+	maxDigits := n - max(minLetters, 0)
+
+	probability := 0.0
+	for k := minDigits; k <= maxDigits; k++ {
+		probability += binomial(n, k) * math.Pow(pd, float64(k)) * math.Pow(1-pd, float64(n-k))
+	}
+	return math.Min(probability, 1)
+}
+
+// binomial returns the binomial coefficient n choose k.
+func binomial(n int, k int) float64 {
+	result := 1.0
+	for i := 1; i <= k; i++ {
+		result *= float64(n-k+i) / float64(i)
+	}
+	return result
+}
+
+// measureRate measures how many addresses per second the matcher's generator produces and
+// checks with the given number of goroutines, by running them for the given duration.
+func measureRate(m matcher, goroutines int, duration time.Duration) float64 {
+	m = m.prepare()
+	m.Generator = benchmarkGenerator(m.Generator)
+
+	var attempts atomic.Uint64
+	var wg sync.WaitGroup
+	quit := make(chan struct{})
+	start := time.Now()
+
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-quit:
+					return
+				default:
+					m.Match(m.GenerateWallet().Address)
+					attempts.Add(1)
+				}
+			}
+		}()
+	}
+
+	time.Sleep(duration)
+	close(quit)
+	wg.Wait()
+
+	return float64(attempts.Load()) / time.Since(start).Seconds()
+}
+
+// benchmarkGenerator returns a generator that behaves like g but can be benchmarked without
+// side effects on g. Benchmarking a HD walk would otherwise skip the benchmarked paths.
+func benchmarkGenerator(g Generator) Generator {
+	if walk, ok := g.(hdWalkGenerator); ok {
+		return walk.fork()
+	}
+	return g
+}
+
+// formatCount formats a large number with an SI suffix.
+func formatCount(n float64) string {
+	switch {
+	case math.IsInf(n, 0):
+		return "infinity"
+	case n >= 1e15:
+		return fmt.Sprintf("%.2e", n)
+	case n >= 1e12:
+		return fmt.Sprintf("%.2fT", n/1e12)
+	case n >= 1e9:
+		return fmt.Sprintf("%.2fG", n/1e9)
+	case n >= 1e6:
+		return fmt.Sprintf("%.2fM", n/1e6)
+	case n >= 1e4:
+		return fmt.Sprintf("%.2fk", n/1e3)
+	default:
+		return fmt.Sprintf("%.0f", n)
+	}
+}
+
+// formatSeconds formats a duration in seconds in the largest fitting unit, up to years.
+func formatSeconds(seconds float64) string {
+	switch {
+	case math.IsInf(seconds, 0) || math.IsNaN(seconds):
+		return "forever"
+	case seconds >= 1e4*365*24*3600:
+		return formatCount(seconds/(365*24*3600)) + " years"
+	case seconds >= 365*24*3600:
+		return fmt.Sprintf("%.1f years", seconds/(365*24*3600))
+	case seconds >= 24*3600:
+		return fmt.Sprintf("%.1f days", seconds/(24*3600))
+	case seconds >= 3600:
+		return fmt.Sprintf("%.1f hours", seconds/3600)
+	case seconds >= 60:
+		return fmt.Sprintf("%.1f minutes", seconds/60)
+	case seconds >= 1:
+		return fmt.Sprintf("%.1f seconds", seconds)
+	default:
+		return fmt.Sprintf("%.0f ms", seconds*1000)
+	}
+}
+
+// estimateCommand implements the estimate subcommand. It prints the difficulty of a search
+// and the expected time to find a match, based on a short benchmark of this machine.
+func estimateCommand(args []string) error {
+	flags := pflag.NewFlagSet("estimate", pflag.ContinueOnError)
+	var matcherMode = flags.StringP("mode", "m", "contains", "Matcher mode (contains, starts-with, ends-with, leading-zero-bytes, zero-nibbles, words, runs, repeats, palindromes, sequences)")
+	var searchString = flags.StringP("search", "s", "", "Search string (required)")
+	var chainflag = flags.StringP("chain", "c", "", "Chain selector string (required)")
+	var chainsFile = flags.String("chains-file", "", "YAML or JSON file with extra chain definitions")
+	var letters = flags.IntP("letters", "l", 0, "Amount of letters (a-z) that the address must contain")
+	var digits = flags.IntP("digits", "d", 0, "Amount of digits (0-9) that the address must contain")
+	var useMnemonic = flags.Bool("mnemonic", false, "Benchmark deriving candidates from BIP39 mnemonics")
+	var rate = flags.Float64("rate", 0, "Keys per second to assume instead of running a benchmark")
+	var benchmark = flags.Duration("benchmark", 2*time.Second, "Duration of the benchmark")
+	var threads = flags.IntP("threads", "t", runtime.NumCPU(), "Number of goroutines to benchmark")
+	var maskFlag = flags.String("mask", "", "Character classes per position of the address, e.g. ????dddd")
+	var constraintFlags = flags.StringArray("constraint", nil, "Count of digits or letters in the address or a region, e.g. digits=8@-8:")
+	var wordlist = flags.String("wordlist", "", "File with one word per line for the words mode")
+	var leet = flags.Bool("leetspeak", false, "Match words with leetspeak substitutions")
+	var caseMode = flags.String("case", "insensitive", "Case matching of EVM addresses (insensitive, checksum)")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: vanity-forge estimate -c <chain> -m <mode> -s <search string>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *chainflag == "" || *searchString == "" {
+		flags.Usage()
+		return errors.New("--chain and --search are required")
+	}
+	if *threads < 1 {
+		return errors.New("Invalid number of threads. Must be at least 1")
+	}
+	if !slices.Contains(MatcherModes, *matcherMode) {
+		return errors.New("Invalid matcher mode. Must be one of: " + strings.Join(MatcherModes, ", "))
+	}
+	if !slices.Contains(CaseModes, *caseMode) {
+		return errors.New("Invalid case mode. Must be one of: insensitive, checksum")
+	}
+
+	if err := loadAvailableChains(*chainsFile); err != nil {
+		return fmt.Errorf("Invalid chains file %w", err)
+	}
+	selectedChain, err := findChain(*chainflag)
+	if err != nil {
+		return err
+	}
+
+	var words []string
+	if *wordlist != "" {
+		if words, err = readWordlist(*wordlist); err != nil {
+			return err
+		}
+	}
+
+	addressMask, err := parseMask(*maskFlag)
+	if err != nil {
+		return err
+	}
+	var constraints []countConstraint
+	for _, s := range *constraintFlags {
+		c, err := parseConstraint(s)
+		if err != nil {
+			return fmt.Errorf("Invalid constraint %w", err)
+		}
+		constraints = append(constraints, c)
+	}
+
+	m := matcher{
+		Mode:            *matcherMode,
+		SearchString:    strings.ToLower(*searchString),
+		Chain:           selectedChain,
+		RequiredLetters: *letters,
+		RequiredDigits:  *digits,
+		CaseSensitive:   *caseMode == "checksum",
+		Words:           words,
+		Leetspeak:       *leet,
+		Mask:            addressMask,
+		Constraints:     constraints,
+	}
+	if m.CaseSensitive {
+		m.SearchString = *searchString
+	}
+	if errs := m.ValidateInput(); len(errs) > 0 {
+		return errors.New(strings.TrimPrefix(strings.Join(errs, "\n"), "ERROR: "))
+	}
+
+	if *useMnemonic {
+		m.Generator, err = newMnemonicGenerator(m.mustGenerator(), 24, defaultHDPath(selectedChain.CoinType))
+		if err != nil {
+			return err
+		}
+	}
+
+	d, err := m.Difficulty()
+	if err != nil {
+		return err
+	}
+	if d.Probability == 0 {
+		return errors.New("no address can match")
+	}
+
+	d.Rate = *rate
+	if d.Rate <= 0 {
+		fmt.Fprintf(os.Stderr, "Benchmarking %s on %d threads...\n", formatSeconds(benchmark.Seconds()), *threads)
+		d.Rate = measureRate(m, *threads, *benchmark)
+	}
+
+	fmt.Println(d)
+	return nil
+}
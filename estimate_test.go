@@ -0,0 +1,83 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatcher_Difficulty(t *testing.T) {
+	cosmos := AvailableChains[1]
+	berachain := AvailableChains[3]
+
+	d, err := matcher{Mode: "starts-with", SearchString: "acde", Chain: cosmos}.Difficulty()
+	assert.NoError(t, err)
+	assert.InDelta(t, math.Pow(32, 4), d.ExpectedAttempts(), 1e-6)
+
+	d, err = matcher{Mode: "ends-with", SearchString: "acde", Chain: berachain}.Difficulty()
+	assert.NoError(t, err)
+	assert.InDelta(t, math.Pow(16, 4), d.ExpectedAttempts(), 1e-6)
+
+	// A substring can appear at any of the 35 positions of a 38 character body
+	d, err = matcher{Mode: "contains", SearchString: "acde", Chain: cosmos}.Difficulty()
+	assert.NoError(t, err)
+	assert.InDelta(t, 1-math.Pow(1-math.Pow(32, -4), 35), d.Probability, 1e-12)
+
+	// Required digits make the search harder, digits in the search string count towards them
+	easy, err := matcher{Mode: "starts-with", SearchString: "a1", Chain: berachain, RequiredDigits: 1}.Difficulty()
+	assert.NoError(t, err)
+	assert.InDelta(t, math.Pow(16, -2), easy.Probability, 1e-12)
+
+	hard, err := matcher{Mode: "starts-with", SearchString: "ab", Chain: berachain, RequiredDigits: 30}.Difficulty()
+	assert.NoError(t, err)
+	assert.Less(t, hard.Probability, easy.Probability/10)
+
+	impossible, err := matcher{Mode: "starts-with", SearchString: "a", Chain: cosmos, RequiredDigits: 38}.Difficulty()
+	assert.NoError(t, err)
+	assert.Zero(t, impossible.Probability)
+
+	// Every cased letter of a checksum search doubles the expected attempts, digits don't
+	d, err = matcher{Mode: "starts-with", SearchString: "De4d", Chain: berachain, CaseSensitive: true}.Difficulty()
+	assert.NoError(t, err)
+	assert.InDelta(t, math.Pow(16, 4)*8, d.ExpectedAttempts(), 1e-6)
+
+	_, err = matcher{Mode: "regex", SearchString: "^a", Chain: cosmos}.Difficulty()
+	assert.ErrorIs(t, err, errEstimateUnsupported)
+}
+
+func TestDifficulty_QuantileAttempts(t *testing.T) {
+	d := difficulty{Probability: 1.0 / 1024, Rate: 1024}
+	assert.InDelta(t, 709.5, d.QuantileAttempts(0.5), 0.5)
+	assert.InDelta(t, math.Log(100)*1024, d.QuantileAttempts(0.99), 10)
+	assert.InDelta(t, 1, d.Seconds(d.ExpectedAttempts()), 1e-9)
+	assert.Contains(t, d.String(), "50% chance within 693 ms")
+}
+
+func TestRequiredCharsProbability(t *testing.T) {
+	assert.InDelta(t, 1, requiredCharsProbability(10, 0.5, 0, 0), 1e-12)
+	assert.InDelta(t, math.Pow(0.5, 10), requiredCharsProbability(10, 0.5, 10, 0), 1e-12)
+	assert.InDelta(t, 1-math.Pow(0.5, 10), requiredCharsProbability(10, 0.5, 1, 0), 1e-12)
+	assert.Zero(t, requiredCharsProbability(10, 0.5, 6, 5))
+}
+
+func TestSearchWarning(t *testing.T) {
+	assert.Empty(t, searchWarning(difficulty{Probability: 1e-6, Rate: 1e6}, 1))
+	assert.Empty(t, searchWarning(difficulty{Probability: 1e-12}, 1))
+	assert.Contains(t, searchWarning(difficulty{Probability: 1e-12, Rate: 1e6}, 2), "2 matching accounts is expected to take 23.1 days")
+	assert.Contains(t, searchWarning(difficulty{}, 1), "No address can match")
+}
+
+func TestMeasureRate(t *testing.T) {
+	m := matcher{Mode: "contains", SearchString: "a", Chain: AvailableChains[0]}
+	assert.Greater(t, measureRate(m, 2, 50*time.Millisecond), 0.0)
+}
+
+func TestFormatSeconds(t *testing.T) {
+	assert.Equal(t, "500 ms", formatSeconds(0.5))
+	assert.Equal(t, "1.5 minutes", formatSeconds(90))
+	assert.Equal(t, "2.0 days", formatSeconds(2*24*3600))
+	assert.Equal(t, "1.00M years", formatSeconds(1e6*365*24*3600))
+	assert.Equal(t, "forever", formatSeconds(math.Inf(1)))
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"crypto/ecdsa"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ethsecp256k1Wallet represents an Ethereum style secp256k1 wallet on a Cosmos chain,
+// as used by Evmos, Injective, Dymension and other EVM compatible Cosmos chains.
+// The address is the Keccak-256 based 20 byte Ethereum address, bech32 encoded with the chain prefix,
+// so it shares the Bech32 alphabet and validation with secp256k1Wallet.
+type ethsecp256k1Wallet struct {
+	secp256k1Wallet
+}
+
+func init() {
+	RegisterGenerator(Ethsecp256k1, func(c chain) Generator {
+		return ethsecp256k1Wallet{secp256k1Wallet{Chain: c}}
+	})
+}
+
+// GenerateWallet generates a new ethsecp256k1 wallet.
+func (w ethsecp256k1Wallet) GenerateWallet() wallet {
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		panic(err)
+	}
+
+	generated, err := w.walletFromECDSA(privateKey)
+	if err != nil {
+		panic(err)
+	}
+
+	return generated
+}
+
+// WalletFromPrivateKey derives the ethsecp256k1 wallet of a private key.
+func (w ethsecp256k1Wallet) WalletFromPrivateKey(privateKey []byte) (wallet, error) {
+	privateKeyECDSA, err := crypto.ToECDSA(privateKey)
+	if err != nil {
+		return wallet{}, err
+	}
+
+	return w.walletFromECDSA(privateKeyECDSA)
+}
+
+// WalletFromPublicKey derives the ethsecp256k1 wallet of a public key.
+func (w ethsecp256k1Wallet) WalletFromPublicKey(publicKey []byte) (wallet, error) {
+	pub, err := parsePublicKey(publicKey)
+	if err != nil {
+		return wallet{}, err
+	}
+
+	return w.walletFromPublicKeyECDSA(pub)
+}
+
+// walletFromECDSA derives the wallet of a private key.
+func (w ethsecp256k1Wallet) walletFromECDSA(privateKey *ecdsa.PrivateKey) (wallet, error) {
+	derived, err := w.walletFromPublicKeyECDSA(&privateKey.PublicKey)
+	if err != nil {
+		return wallet{}, err
+	}
+
+	derived.PrivateKey = crypto.FromECDSA(privateKey)
+	return derived, nil
+}
+
+// walletFromPublicKeyECDSA derives the address of a public key.
+// The public key is stored in compressed form, like the ethsecp256k1 public keys on chain,
+// and the 0x form of the address is reported in HexAddress.
+func (w ethsecp256k1Wallet) walletFromPublicKeyECDSA(publicKey *ecdsa.PublicKey) (wallet, error) {
+	address := crypto.PubkeyToAddress(*publicKey)
+	bech32Addr, err := bech32.ConvertAndEncode(w.Chain.Prefix, address.Bytes())
+	if err != nil {
+		return wallet{}, err
+	}
+
+	return wallet{
+		Address:    bech32Addr,
+		PublicKey:  crypto.CompressPubkey(publicKey),
+		HexAddress: address.Hex(),
+	}, nil
+}
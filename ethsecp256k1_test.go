@@ -0,0 +1,37 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEthsecp256k1Wallet_GenerateWallet(t *testing.T) {
+	wallet := ethsecp256k1Wallet{secp256k1Wallet{Chain: chain{Prefix: "inj", PrefixFull: "inj1", Encryption: Ethsecp256k1}}}
+	w := wallet.GenerateWallet()
+
+	assert.True(t, strings.HasPrefix(w.Address, "inj1"))
+	assert.Len(t, strings.TrimPrefix(w.Address, "inj1"), wallet.AddressLength())
+	assert.Len(t, w.PublicKey, 33)
+
+	// The bech32 address and the hex address encode the same bytes
+	hrp, addressBytes, err := bech32.DecodeAndConvert(w.Address)
+	assert.NoError(t, err)
+	assert.Equal(t, "inj", hrp)
+	assert.Equal(t, common.HexToAddress(w.HexAddress).Bytes(), addressBytes)
+
+	// The address is derived from the private key with Keccak-256
+	privateKey, err := crypto.ToECDSA(w.PrivateKey)
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(privateKey.PublicKey).Hex(), w.HexAddress)
+}
+
+func TestEthsecp256k1Wallet_Registered(t *testing.T) {
+	m := matcher{Mode: "contains", SearchString: "acde", Chain: chain{Prefix: "evmos", PrefixFull: "evmos1", Encryption: Ethsecp256k1}}
+	assert.Empty(t, m.ValidateInput())
+	assert.IsType(t, ethsecp256k1Wallet{}, m.mustGenerator())
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Generator generates wallets and validates search input for one encryption/address family.
+// Adding support for a new chain family means implementing this interface and registering it
+// with RegisterGenerator.
+type Generator interface {
+	// GenerateWallet generates a new random wallet.
+	GenerateWallet() wallet
+
+	// WalletFromPrivateKey derives the wallet of a raw 32 byte private key.
+	WalletFromPrivateKey(privateKey []byte) (wallet, error)
+
+	// WalletFromPublicKey derives the wallet of a compressed or uncompressed secp256k1 public key.
+	// The private key of the returned wallet is empty.
+	WalletFromPublicKey(publicKey []byte) (wallet, error)
+
+	// ValidateInput validates the search string, required letters, and required digits.
+	// It returns a list of errors encountered during validation.
+	ValidateInput(SearchString string, RequiredLetters int, RequiredDigits int) []string
+
+	// CheckRequiredDigits checks if a candidate string contains the required number of digits.
+	CheckRequiredDigits(candidate string, required int) bool
+
+	// CheckRequiredLetters checks if a candidate string contains the required number of letters.
+	CheckRequiredLetters(candidate string, required int) bool
+
+	// Alphabet returns every character that may appear in the address after the full prefix.
+	Alphabet() string
+
+	// Digits returns the digit character class of the alphabet.
+	Digits() string
+
+	// Letters returns the letter character class of the alphabet.
+	Letters() string
+
+	// AddressLength returns the number of characters in the address after the full prefix.
+	AddressLength() int
+}
+
+// GeneratorFactory creates a Generator for the given chain.
+type GeneratorFactory func(c chain) Generator
+
+// generators holds the registered generator factories keyed by encryption.
+var generators = map[Encryption]GeneratorFactory{}
+
+// RegisterGenerator registers a generator factory for the given encryption.
+// It panics if a generator is already registered for that encryption.
+func RegisterGenerator(e Encryption, factory GeneratorFactory) {
+	if _, ok := generators[e]; ok {
+		panic(fmt.Sprintf("generator already registered for encryption %s", e))
+	}
+	generators[e] = factory
+}
+
+// NewGenerator returns the Generator registered for the chain's encryption.
+// It returns an error if no generator is registered for it.
+func NewGenerator(c chain) (Generator, error) {
+	factory, ok := generators[c.Encryption]
+	if !ok {
+		return nil, fmt.Errorf("no generator registered for encryption %s", c.Encryption)
+	}
+	return factory(c), nil
+}
+
+// parsePublicKey parses a 33 byte compressed or 65 byte uncompressed secp256k1 public key.
+// All registered encryption families use secp256k1 keys.
+func parsePublicKey(publicKey []byte) (*ecdsa.PublicKey, error) {
+	if len(publicKey) == 33 {
+		return crypto.DecompressPubkey(publicKey)
+	}
+	return crypto.UnmarshalPubkey(publicKey)
+}
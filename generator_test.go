@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewGenerator(t *testing.T) {
+	g, err := NewGenerator(chain{Prefix: "cosmos", PrefixFull: "cosmos1", Encryption: Secp256k1})
+	assert.NoError(t, err)
+	assert.IsType(t, secp256k1Wallet{}, g)
+	assert.Equal(t, 38, g.AddressLength())
+
+	g, err = NewGenerator(chain{Prefix: "0x", PrefixFull: "0x", Encryption: ECSDA})
+	assert.NoError(t, err)
+	assert.IsType(t, ecsdaWallet{}, g)
+	assert.Equal(t, 40, g.AddressLength())
+
+	_, err = NewGenerator(chain{Encryption: Undefined})
+	assert.Error(t, err)
+}
+
+func TestRegisterGenerator_Duplicate(t *testing.T) {
+	assert.Panics(t, func() {
+		RegisterGenerator(Secp256k1, func(c chain) Generator { return secp256k1Wallet{Chain: c} })
+	})
+}
+
+func TestMatcher_ValidateInput_UnregisteredEncryption(t *testing.T) {
+	m := matcher{Mode: "contains", SearchString: "acde", Chain: chain{Encryption: Undefined}}
+	errs := m.ValidateInput()
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0], "no generator registered")
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/go-bip39"
+)
+
+// hdMaxIndex is the number of non-hardened and hardened BIP32 child indices.
+const hdMaxIndex = 1 << 31
+
+// hdWalkGenerator wraps a Generator to walk the BIP44 account and address index space of one
+// fixed mnemonic, instead of generating random keys. Every call to GenerateWallet derives the
+// next path, and the counter is shared between copies so goroutines walk disjoint paths in parallel.
+type hdWalkGenerator struct {
+	Generator
+	CoinType  uint32 // BIP44 coin type
+	Accounts  uint32 // number of accounts walked side by side
+	master    [32]byte
+	chainCode [32]byte
+	next      *atomic.Uint64
+}
+
+// newHDWalkGenerator returns a hdWalkGenerator walking the paths of mnemonic with generator g.
+// It returns an error if the mnemonic is invalid or no accounts are walked.
+func newHDWalkGenerator(g Generator, mnemonic string, coinType uint32, accounts uint32) (hdWalkGenerator, error) {
+	if accounts == 0 || accounts > hdMaxIndex {
+		return hdWalkGenerator{}, fmt.Errorf("invalid number of HD accounts %d, must be between 1 and %d", accounts, uint64(hdMaxIndex))
+	}
+
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, "")
+	if err != nil {
+		return hdWalkGenerator{}, fmt.Errorf("invalid mnemonic: %w", err)
+	}
+
+	master, chainCode := hd.ComputeMastersFromSeed(seed)
+
+	return hdWalkGenerator{
+		Generator: g,
+		CoinType:  coinType,
+		Accounts:  accounts,
+		master:    master,
+		chainCode: chainCode,
+		next:      new(atomic.Uint64),
+	}, nil
+}
+
+// Path returns the BIP44 path of the nth wallet in the walk.
+// The walk goes through the address indices of all walked accounts side by side,
+// m/44'/c'/0'/0/0, m/44'/c'/1'/0/0, ..., m/44'/c'/0'/0/1, ..., and moves on to the
+// next block of accounts once every address index of the current block is exhausted.
+func (g hdWalkGenerator) Path(n uint64) string {
+	accounts := uint64(g.Accounts)
+	block := n / (accounts * hdMaxIndex)
+	account := block*accounts + n%accounts
+	index := (n / accounts) % hdMaxIndex
+
+	return hd.CreateHDPath(g.CoinType, uint32(account), uint32(index)).String()
+}
+
+// GenerateWallet derives the wallet at the next path of the walk.
+// The mnemonic is not included in the wallet, only the path that produced it.
+func (g hdWalkGenerator) GenerateWallet() wallet {
+	path := g.Path(g.next.Add(1) - 1)
+
+	privateKey, err := hd.DerivePrivateKeyForPath(g.master, g.chainCode, path)
+	if err != nil {
+		panic(err)
+	}
+
+	derived, err := g.Generator.WalletFromPrivateKey(privateKey)
+	if err != nil {
+		panic(err)
+	}
+
+	derived.HDPath = path
+
+	return derived
+}
+
+// fork returns a copy of the walk with its own counter, starting again at the first path.
+func (g hdWalkGenerator) fork() hdWalkGenerator {
+	g.next = new(atomic.Uint64)
+	return g
+}
+
+// readMnemonic reads a mnemonic from a file, or from stdin if path is "-".
+// Whitespace between the words is normalized.
+func readMnemonic(path string) (string, error) {
+	var data []byte
+	var err error
+
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	mnemonic := strings.Join(strings.Fields(string(data)), " ")
+	if mnemonic == "" {
+		return "", errors.New("mnemonic is empty")
+	}
+
+	return mnemonic, nil
+}
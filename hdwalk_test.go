@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHDWalkGenerator_Path(t *testing.T) {
+	g, err := newHDWalkGenerator(ecsdaWallet{}, testMnemonic, 60, 3)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "m/44'/60'/0'/0/0", g.Path(0))
+	assert.Equal(t, "m/44'/60'/1'/0/0", g.Path(1))
+	assert.Equal(t, "m/44'/60'/2'/0/0", g.Path(2))
+	assert.Equal(t, "m/44'/60'/0'/0/1", g.Path(3))
+	assert.Equal(t, "m/44'/60'/2'/0/2147483647", g.Path(3*hdMaxIndex-1))
+	assert.Equal(t, "m/44'/60'/3'/0/0", g.Path(3*hdMaxIndex))
+}
+
+func TestHDWalkGenerator_GenerateWallet(t *testing.T) {
+	g, err := newHDWalkGenerator(ecsdaWallet{}, testMnemonic, 60, 1)
+	assert.NoError(t, err)
+
+	w := g.GenerateWallet()
+	assert.Equal(t, "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266", w.Address)
+	assert.Equal(t, "m/44'/60'/0'/0/0", w.HDPath)
+	assert.Empty(t, w.Mnemonic)
+
+	// Copies share the walk
+	copied := g
+	w = copied.GenerateWallet()
+	assert.Equal(t, "0x70997970C51812dc3A010C7d01b50e0d17dc79C8", w.Address)
+	assert.Equal(t, "m/44'/60'/0'/0/1", w.HDPath)
+}
+
+func TestNewHDWalkGenerator_Invalid(t *testing.T) {
+	_, err := newHDWalkGenerator(ecsdaWallet{}, "test test test", 60, 1)
+	assert.ErrorContains(t, err, "invalid mnemonic")
+
+	_, err = newHDWalkGenerator(ecsdaWallet{}, testMnemonic, 60, 0)
+	assert.ErrorContains(t, err, "invalid number of HD accounts")
+}
+
+func TestReadMnemonic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mnemonic")
+	assert.NoError(t, os.WriteFile(path, []byte("  test test test test test test\ntest test test test test   junk\n"), 0o600))
+
+	mnemonic, err := readMnemonic(path)
+	assert.NoError(t, err)
+	assert.Equal(t, testMnemonic, mnemonic)
+
+	assert.NoError(t, os.WriteFile(path, []byte("\n"), 0o600))
+	_, err = readMnemonic(path)
+	assert.ErrorContains(t, err, "mnemonic is empty")
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// keyringBackends are the cosmos-sdk keyring backends wallets can be imported into.
+var keyringBackends = []string{keyring.BackendFile, keyring.BackendTest}
+
+// keyringImporter imports wallets into a cosmos-sdk keyring directory, so they can be used with
+// celestia-appd, gaiad or any other cosmos-sdk binary by passing the same --keyring-dir and
+// --keyring-backend.
+type keyringImporter struct {
+	kr      keyring.Keyring
+	KeyName string
+}
+
+// newKeyringImporter opens the keyring with the given backend in dir. The file backend asks for
+// its passphrase on userInput, or on the terminal if there is one, when the keyring is first read.
+func newKeyringImporter(backend string, dir string, keyName string, userInput io.Reader) (keyringImporter, error) {
+	if backend != keyring.BackendFile && backend != keyring.BackendTest {
+		return keyringImporter{}, fmt.Errorf("unsupported keyring backend %q, must be one of: %v", backend, keyringBackends)
+	}
+	if keyName == "" {
+		return keyringImporter{}, errors.New("keyring key name can't be empty")
+	}
+
+	registry := codectypes.NewInterfaceRegistry()
+	cryptocodec.RegisterInterfaces(registry)
+
+	kr, err := keyring.New("vanity-forge", backend, dir, userInput, codec.NewProtoCodec(registry))
+	if err != nil {
+		return keyringImporter{}, err
+	}
+
+	return keyringImporter{kr: kr, KeyName: keyName}, nil
+}
+
+// keyringNeedsPassphrase reports whether the keyring backend asks for a passphrase.
+func keyringNeedsPassphrase(backend string) bool {
+	return backend == keyring.BackendFile
+}
+
+// keyringSupported reports whether wallets of the chain can be imported into a cosmos-sdk keyring.
+func keyringSupported(c chain) bool {
+	return c.Encryption == Secp256k1
+}
+
+// UID returns the key name of the ith (zero based) of total imported wallets.
+// A single wallet is imported under the key name itself, several wallets get a numbered suffix.
+func (k keyringImporter) UID(i int, total int) string {
+	if total == 1 {
+		return k.KeyName
+	}
+	return k.KeyName + "-" + strconv.Itoa(i+1)
+}
+
+// CheckKeyNames checks that none of the key names of total imported wallets exists in the keyring.
+// Reading the keyring asks for the passphrase of the file backend, so backend errors and existing
+// key names are reported before a search starts instead of after it.
+func (k keyringImporter) CheckKeyNames(total int) error {
+	for i := 0; i < total; i++ {
+		uid := k.UID(i, total)
+		_, err := k.kr.Key(uid)
+		if err == nil {
+			return fmt.Errorf("a key named %q already exists in the keyring", uid)
+		}
+		if !errors.Is(err, sdkerrors.ErrKeyNotFound) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Import imports the wallet's private key under uid.
+// It returns an error if a key with that name already exists.
+func (k keyringImporter) Import(uid string, w wallet) error {
+	if w.PartialKey {
+		return errors.New("can't import a split-key partial key")
+	}
+
+	return k.kr.ImportPrivKeyHex(uid, hex.EncodeToString(w.PrivateKey), string(hd.Secp256k1Type))
+}
+
+// ExportArmor returns the ASCII-armored private key of uid, encrypted with passphrase,
+// in the same format as the keys export command of cosmos-sdk binaries.
+func (k keyringImporter) ExportArmor(uid string, passphrase string) (string, error) {
+	return k.kr.ExportPrivKeyArmor(uid, passphrase)
+}
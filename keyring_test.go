@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyringImporter_Import(t *testing.T) {
+	importer, err := newKeyringImporter("test", t.TempDir(), "vanity", strings.NewReader(""))
+	assert.NoError(t, err)
+
+	w := secp256k1Wallet{Chain: AvailableChains[0]}.GenerateWallet()
+	assert.NoError(t, importer.Import("vanity", w))
+
+	record, err := importer.kr.Key("vanity")
+	assert.NoError(t, err)
+	address, err := record.GetAddress()
+	assert.NoError(t, err)
+	bech32Addr, err := bech32.ConvertAndEncode("celestia", address)
+	assert.NoError(t, err)
+	assert.Equal(t, w.Address, bech32Addr)
+
+	armor, err := importer.ExportArmor("vanity", "hunter2")
+	assert.NoError(t, err)
+	assert.Contains(t, armor, "BEGIN TENDERMINT PRIVATE KEY")
+
+	// Existing keys are never overwritten
+	assert.Error(t, importer.Import("vanity", secp256k1Wallet{Chain: AvailableChains[0]}.GenerateWallet()))
+
+	assert.ErrorContains(t, importer.Import("partial", wallet{PrivateKey: w.PrivateKey, PartialKey: true}), "partial key")
+}
+
+func TestKeyringImporter_CheckKeyNames(t *testing.T) {
+	importer, err := newKeyringImporter("test", t.TempDir(), "vanity", strings.NewReader(""))
+	assert.NoError(t, err)
+	assert.NoError(t, importer.CheckKeyNames(1))
+
+	assert.NoError(t, importer.Import("vanity-2", secp256k1Wallet{Chain: AvailableChains[0]}.GenerateWallet()))
+	assert.NoError(t, importer.CheckKeyNames(1))
+	assert.ErrorContains(t, importer.CheckKeyNames(3), `a key named "vanity-2" already exists`)
+}
+
+func TestKeyringImporter_UID(t *testing.T) {
+	importer := keyringImporter{KeyName: "vanity"}
+	assert.Equal(t, "vanity", importer.UID(0, 1))
+	assert.Equal(t, "vanity-1", importer.UID(0, 3))
+	assert.Equal(t, "vanity-3", importer.UID(2, 3))
+}
+
+func TestNewKeyringImporter_Invalid(t *testing.T) {
+	_, err := newKeyringImporter("os", t.TempDir(), "vanity", strings.NewReader(""))
+	assert.ErrorContains(t, err, "unsupported keyring backend")
+
+	_, err = newKeyringImporter("test", t.TempDir(), "", strings.NewReader(""))
+	assert.ErrorContains(t, err, "key name can't be empty")
+}
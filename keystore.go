@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// keystoreExporter writes wallets as scrypt encrypted Ethereum V3 JSON keystore files,
+// named UTC--<created at>--<address> like geth does, so they can be imported into geth,
+// Foundry's cast wallet or MetaMask.
+type keystoreExporter struct {
+	ks         *keystore.KeyStore
+	passphrase string
+}
+
+// newKeystoreExporter returns a keystoreExporter writing into dir with the scrypt parameters n and p.
+// It returns an error if the passphrase is empty or dir can't be created.
+func newKeystoreExporter(dir string, passphrase string, n int, p int) (keystoreExporter, error) {
+	if passphrase == "" {
+		return keystoreExporter{}, errors.New("keystore passphrase can't be empty")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return keystoreExporter{}, err
+	}
+
+	return keystoreExporter{ks: keystore.NewKeyStore(dir, n, p), passphrase: passphrase}, nil
+}
+
+// keystoreSupported reports whether wallets of the chain have EVM keys that can be exported to a keystore.
+func keystoreSupported(c chain) bool {
+	return c.Encryption == ECSDA || c.Encryption == Ethsecp256k1
+}
+
+// Export encrypts the wallet's private key into a new keystore file and returns its path.
+func (e keystoreExporter) Export(w wallet) (string, error) {
+	if w.PartialKey {
+		return "", errors.New("can't export a split-key partial key")
+	}
+
+	privateKey, err := crypto.ToECDSA(w.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+
+	account, err := e.ks.ImportECDSA(privateKey, e.passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	return account.URL.Path, nil
+}
+
+// readPassphrase reads a passphrase from the first line of r.
+func readPassphrase(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// readPassphraseFD reads a passphrase from the first line of an open file descriptor.
+func readPassphraseFD(fd int) (string, error) {
+	file := os.NewFile(uintptr(fd), fmt.Sprintf("fd%d", fd))
+	if file == nil {
+		return "", fmt.Errorf("invalid file descriptor %d", fd)
+	}
+	defer file.Close()
+
+	return readPassphrase(file)
+}
+
+// promptPassphrase asks for a new passphrase twice without echoing it.
+func promptPassphrase(title string) (string, error) {
+	var passphrase, confirmation string
+
+	err := huh.NewInput().
+		Title(title).
+		Password(true).
+		Value(&passphrase).
+		Run()
+	if err != nil {
+		return "", err
+	}
+
+	err = huh.NewInput().
+		Title("Repeat " + strings.ToLower(title)).
+		Password(true).
+		Value(&confirmation).
+		Run()
+	if err != nil {
+		return "", err
+	}
+
+	if passphrase != confirmation {
+		return "", errors.New("passphrases don't match")
+	}
+
+	return passphrase, nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeystoreExporter_Export(t *testing.T) {
+	dir := t.TempDir()
+	exporter, err := newKeystoreExporter(dir, "hunter2", keystore.LightScryptN, keystore.LightScryptP)
+	assert.NoError(t, err)
+
+	w := ecsdaWallet{}.GenerateWallet()
+	path, err := exporter.Export(w)
+	assert.NoError(t, err)
+	assert.Equal(t, dir, filepath.Dir(path))
+	assert.True(t, strings.HasPrefix(filepath.Base(path), "UTC--"))
+	assert.True(t, strings.HasSuffix(filepath.Base(path), "--"+strings.ToLower(strings.TrimPrefix(w.Address, "0x"))))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	key, err := keystore.DecryptKey(data, "hunter2")
+	assert.NoError(t, err)
+	assert.Equal(t, w.Address, key.Address.Hex())
+
+	_, err = keystore.DecryptKey(data, "wrong")
+	assert.Error(t, err)
+
+	_, err = exporter.Export(wallet{PrivateKey: w.PrivateKey, PartialKey: true})
+	assert.ErrorContains(t, err, "partial key")
+}
+
+func TestNewKeystoreExporter_EmptyPassphrase(t *testing.T) {
+	_, err := newKeystoreExporter(t.TempDir(), "", keystore.LightScryptN, keystore.LightScryptP)
+	assert.ErrorContains(t, err, "can't be empty")
+}
+
+func TestReadPassphrase(t *testing.T) {
+	passphrase, err := readPassphrase(strings.NewReader("hunter2\r\nignored\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hunter2", passphrase)
+
+	passphrase, err = readPassphrase(strings.NewReader("no newline"))
+	assert.NoError(t, err)
+	assert.Equal(t, "no newline", passphrase)
+}
@@ -1,30 +1,101 @@
 package main
 
 import (
+	"context"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"os"
+	"os/signal"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
 
 	"golang.org/x/exp/slices"
 
 	"github.com/charmbracelet/huh"
-	"github.com/charmbracelet/huh/spinner"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/spf13/pflag"
 )
 
+// exitInterrupted is the exit code of a search stopped by SIGINT or SIGTERM.
+const exitInterrupted = 130
+
+// exitBudgetExhausted is the exit code of a search stopped by --max-duration or --max-attempts
+// before all requested accounts were found.
+const exitBudgetExhausted = 3
+
+// commands maps subcommand names to their implementations.
+var commands = map[string]func(args []string) error{
+	"import-registry": importRegistryCommand,
+	"split-keygen":    splitKeygenCommand,
+	"combine":         combineCommand,
+	"estimate":        estimateCommand,
+}
+
 func main() {
+	// Run subcommand if one is given
+	if len(os.Args) > 1 {
+		if command, ok := commands[os.Args[1]]; ok {
+			if err := command(os.Args[2:]); err != nil && !errors.Is(err, pflag.ErrHelp) {
+				fmt.Println("ERROR: " + err.Error())
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	// Defined flags
 	var accountsNumber = pflag.IntP("accounts-number", "n", 0, "Amount of accounts you need")
-	var matcherMode = pflag.StringP("mode", "m", "", "Matcher mode (contains, starts-with, ends-with, regex)")
+	var matcherMode = pflag.StringP("mode", "m", "", "Matcher mode (contains, starts-with, ends-with, regex, leading-zero-bytes, zero-nibbles, words, runs, repeats, palindromes, sequences)")
 	var searchString = pflag.StringP("search", "s", "", "Search string")
 	var chainflag = pflag.StringP("chain", "c", "", "Chain selector string")
+	var caseMode = pflag.String("case", "insensitive", "Case matching of EVM addresses (insensitive, checksum), checksum matches the EIP-55 capitalisation of the search")
 	var verbose = pflag.BoolP("verbose", "v", false, "Verbose output")
+	var chainsFile = pflag.String("chains-file", "", "YAML or JSON file with extra chain definitions")
+	var patternsFile = pflag.String("patterns-file", "", "File with one \"<mode> <search string> [count]\" pattern per line, searched in a single pass")
+	var threads = pflag.IntP("threads", "t", runtime.NumCPU(), "Number of goroutines searching in parallel")
+	var maxDuration = pflag.Duration("max-duration", 0, "Stop the search after this duration, e.g. 30m (default unlimited)")
+	var startsWith = pflag.StringArray("starts-with", nil, "Address must start with this string, repeatable and combined with the other conditions")
+	var endsWith = pflag.StringArray("ends-with", nil, "Address must end with this string, repeatable and combined with the other conditions")
+	var containsFlag = pflag.StringArray("contains", nil, "Address must contain this string, repeatable and combined with the other conditions")
+	var notContains = pflag.StringArray("not-contains", nil, "Address must not contain this string, repeatable and combined with the other conditions")
+	var top = pflag.Int("top", 0, "Keep the N best scoring addresses until --max-duration or --max-attempts instead of searching for matches")
+	var scoreWeightsFlag = pflag.String("score-weights", "", "Weights of the scores of --top, e.g. prefix=2,repeats=1 (prefix, repeats, zeros, words, ratio) (default prefix and words if possible, repeats and zeros)")
+	var wordlist = pflag.String("wordlist", "", "File with one word per line for the words mode and the words score of --top")
+	var leet = pflag.Bool("leetspeak", false, "Match words of the words mode with leetspeak substitutions for letters addresses can't contain, e.g. o as 0")
+	var maxAttempts = pflag.Uint64("max-attempts", 0, "Stop the search after generating this many wallets (default unlimited)")
 
 	// Extra flags
 	var letters = pflag.IntP("letters", "l", 0, "Amount of letters (a-z) that the address must contain")
 	var digits = pflag.IntP("digits", "d", 0, "Amount of digits (0-9) that the address must contain")
+	var maskFlag = pflag.String("mask", "", "Character classes per position of the address, e.g. ????dddd or *dddd (? any, d digit, a letter, * any characters)")
+	var constraintFlags = pflag.StringArray("constraint", nil, "Count of digits or letters in the address or a region, e.g. letters<=10 or digits=8@-8: (=, <=, >=), repeatable")
+
+	// Key derivation flags
+	var useMnemonic = pflag.Bool("mnemonic", false, "Derive each candidate from a new BIP39 mnemonic")
+	var mnemonicWords = pflag.Int("mnemonic-words", 24, "Number of mnemonic words (12 or 24)")
+	var hdPath = pflag.String("hd-path", "", "BIP44 derivation path (default m/44'/<chain coin type>'/0'/0/0)")
+	var mnemonicFile = pflag.String("mnemonic-file", "", "Search the HD paths of the mnemonic in this file (- for stdin)")
+	var hdAccounts = pflag.Uint32("hd-accounts", 1, "Number of BIP44 accounts to search side by side with --mnemonic-file")
+	var splitKey = pflag.String("split-key", "", "Public key from split-keygen, search for a partial private key")
+
+	// Export flags
+	var keystoreDir = pflag.String("keystore-dir", "", "Write EVM keys as encrypted V3 keystore files into this directory")
+	var keystorePasswordFD = pflag.Int("keystore-password-fd", -1, "Read the keystore passphrase from this file descriptor instead of prompting")
+	var keyringDir = pflag.String("keyring-dir", "", "Import Cosmos keys into the cosmos-sdk keyring in this directory")
+	var keyringBackend = pflag.String("keyring-backend", "file", "Keyring backend (file, test)")
+	var keyringKeyName = pflag.String("keyring-key-name", "vanity", "Key name in the keyring, numbered when generating several accounts")
+	var keyringExportArmor = pflag.Bool("keyring-export-armor", false, "Print the ASCII-armored private key of each imported key")
+
+	// Output flags
+	var outputFormat = pflag.String("output-format", "text", "Output format of found wallets (text, json, ndjson, csv)")
+	var outFile = pflag.String("out", "", "Write found wallets to this file instead of stdout")
 
 	// Parse flags
 	pflag.Parse()
@@ -32,29 +103,109 @@ func main() {
 	// Validate matcher mode flag if exists
 	if *matcherMode != "" {
 		if !slices.Contains(MatcherModes, *matcherMode) {
-			fmt.Println("ERROR: Invalid matcher mode. Must be one of: contains, starts-with, ends-with, regex")
+			fmt.Println("ERROR: Invalid matcher mode. Must be one of: " + strings.Join(MatcherModes, ", "))
 			os.Exit(1)
 		}
 	}
 
-	// Validate chain flag
-	var selectedChain chain = chain{}
+	// Validate case mode flag
+	if !slices.Contains(CaseModes, *caseMode) {
+		fmt.Println("ERROR: Invalid case mode. Must be one of: insensitive, checksum")
+		os.Exit(1)
+	}
+	caseSensitive := *caseMode == "checksum"
 
-	if *chainflag != "" {
-		var chainnamesslice []string = make([]string, len(AvailableChains))
-		for i, chain := range AvailableChains {
-			chainnamesslice[i] = chain.Name
+	// Validate output format flag
+	if !slices.Contains(OutputFormats, *outputFormat) {
+		fmt.Println("ERROR: Invalid output format. Must be one of: text, json, ndjson, csv")
+		os.Exit(1)
+	}
+
+	// Validate threads flag
+	if *threads < 1 {
+		fmt.Println("ERROR: Invalid number of threads. Must be at least 1")
+		os.Exit(1)
+	}
+
+	// Validate budget flags
+	if *maxDuration < 0 {
+		fmt.Println("ERROR: Invalid max duration. Must not be negative")
+		os.Exit(1)
+	}
+
+	// Read the patterns file, which replaces the matcher mode and search string
+	var patterns []pattern
+	if *patternsFile != "" {
+		if *matcherMode != "" || *searchString != "" {
+			fmt.Println("ERROR: --patterns-file can't be used with --mode or --search.")
+			os.Exit(1)
 		}
 
-		if !slices.Contains(chainnamesslice, *chainflag) {
-			fmt.Println("ERROR: Invalid chain. Must be one of the available chains: ", chainnamesslice)
+		// -n is the default count of every pattern
+		defaultCount := max(*accountsNumber, 1)
+
+		var err error
+		patterns, err = readPatternsFile(*patternsFile, defaultCount, caseSensitive)
+		if err != nil {
+			fmt.Println("ERROR: Invalid patterns file " + err.Error())
 			os.Exit(1)
 		}
 
-		for i, chain := range AvailableChains {
-			if chain.Name == *chainflag {
-				selectedChain = AvailableChains[i]
+		total := 0
+		for _, p := range patterns {
+			total += p.Count
+		}
+		*accountsNumber = total
+	}
+
+	// Combine the condition flags, which replace the matcher mode and search string
+	var conditions []condition
+	for i, searches := range [][]string{*startsWith, *endsWith, *containsFlag, *notContains} {
+		for _, search := range searches {
+			if !caseSensitive {
+				search = strings.ToLower(search)
 			}
+			conditions = append(conditions, condition{Mode: ConditionModes[i], Search: search})
+		}
+	}
+
+	if len(conditions) > 0 && (*matcherMode != "" || *searchString != "" || *patternsFile != "") {
+		fmt.Println("ERROR: --starts-with, --ends-with, --contains and --not-contains can't be used with --mode, --search or --patterns-file.")
+		os.Exit(1)
+	}
+
+	// Validate scoring flags, a scoring run ranks addresses instead of matching a mode
+	if *top < 0 {
+		fmt.Println("ERROR: Invalid top. Must not be negative")
+		os.Exit(1)
+	}
+	if *top > 0 {
+		if *matcherMode != "" || *patternsFile != "" || len(conditions) > 0 {
+			fmt.Println("ERROR: --top can't be used with --mode, --patterns-file or conditions, --search sets the prefix score.")
+			os.Exit(1)
+		}
+		if *maxDuration == 0 && *maxAttempts == 0 {
+			fmt.Println("ERROR: --top needs --max-duration or --max-attempts to end the run.")
+			os.Exit(1)
+		}
+		*accountsNumber = *top
+	}
+
+	// Load extra chains from the chains file flag or the default search path
+	if err := loadAvailableChains(*chainsFile); err != nil {
+		fmt.Println("ERROR: Invalid chains file " + err.Error())
+		os.Exit(1)
+	}
+
+	// Validate chain flag
+	var selectedChain chain = chain{}
+
+	if *chainflag != "" {
+		var err error
+		selectedChain, err = findChain(*chainflag)
+		if err != nil {
+			fmt.Println("ERROR: " + err.Error())
+			os.Exit(1)
 		}
 	}
 
@@ -108,7 +259,7 @@ func main() {
 		selectMatcherModeOptions[i] = huh.NewOption(mode, mode)
 	}
 
-	if settings.MatcherMode == "" {
+	if settings.MatcherMode == "" && len(patterns) == 0 && len(conditions) == 0 && *top == 0 {
 		huh.NewSelect[string]().
 			Title("Matcher Mode").
 			Options(selectMatcherModeOptions...).
@@ -117,7 +268,7 @@ func main() {
 	}
 
 	// Prompt user for missing settings on search string
-	if settings.SearchString == "" {
+	if settings.SearchString == "" && len(patterns) == 0 && len(conditions) == 0 && *top == 0 {
 		huh.NewInput().
 			Title("Search string").
 			CharLimit(38).
@@ -137,13 +288,55 @@ func main() {
 			Run()
 	}
 
+	// Regexes are kept as is, lowercasing would change escapes like \D,
+	// and checksum case matching needs the capitalisation of the search string
+	search := strings.ToLower(settings.SearchString)
+	if settings.MatcherMode == "regex" || caseSensitive {
+		search = settings.SearchString
+	}
+
+	// Parse the mask and the count constraints
+	addressMask, err := parseMask(*maskFlag)
+	if err != nil {
+		fmt.Println("ERROR: " + err.Error())
+		os.Exit(1)
+	}
+
+	var constraints []countConstraint
+	for _, s := range *constraintFlags {
+		c, err := parseConstraint(s)
+		if err != nil {
+			fmt.Println("ERROR: Invalid constraint " + err.Error())
+			os.Exit(1)
+		}
+		constraints = append(constraints, c)
+	}
+
+	// Read the wordlist of the words mode and the words score
+	var words []string
+	if *wordlist != "" {
+		var err error
+		words, err = readWordlist(*wordlist)
+		if err != nil {
+			fmt.Println("ERROR: Can't read wordlist: " + err.Error())
+			os.Exit(1)
+		}
+	}
+
 	// Initialize Matcher struct
 	m := matcher{
 		Mode:            settings.MatcherMode,
-		SearchString:    strings.ToLower(*&settings.SearchString),
+		SearchString:    search,
 		Chain:           *&settings.SelectedChain,
 		RequiredLetters: *&settings.RequiredLetters,
 		RequiredDigits:  *&settings.RequiredDigits,
+		CaseSensitive:   caseSensitive,
+		Patterns:        patterns,
+		Conditions:      conditions,
+		Words:           words,
+		Leetspeak:       *leet,
+		Mask:            addressMask,
+		Constraints:     constraints,
 	}
 
 	matcherValidationErrs := m.ValidateInput()
@@ -155,51 +348,452 @@ func main() {
 		os.Exit(1)
 	}
 
-	var matchingWallet wallet
+	// Weigh the prefix and words scores by default if they can be scored
+	var scoring scorer
+	if *top > 0 {
+		weightsFlag := *scoreWeightsFlag
+		if weightsFlag == "" {
+			weightsFlag = "repeats=1,zeros=1"
+			if m.SearchString != "" {
+				weightsFlag += ",prefix=1"
+			}
+			if *wordlist != "" {
+				weightsFlag += ",words=1"
+			}
+		}
+
+		weights, err := parseScoreWeights(weightsFlag)
+		if err != nil {
+			fmt.Println("ERROR: Invalid score weights " + err.Error())
+			os.Exit(1)
+		}
+
+		scoring, err = newScorer(weights, m.SearchString, words)
+		if err != nil {
+			fmt.Println("ERROR: " + err.Error() + ".")
+			os.Exit(1)
+		}
+	}
+
+	if (*useMnemonic && *mnemonicFile != "") || (*splitKey != "" && (*useMnemonic || *mnemonicFile != "")) {
+		fmt.Println("ERROR: Only one of --mnemonic, --mnemonic-file and --split-key can be used.")
+		os.Exit(1)
+	}
+
+	// The keyring file backend reads its passphrase from stdin, which holds the mnemonic
+	if *mnemonicFile == "-" && *keyringDir != "" && keyringNeedsPassphrase(*keyringBackend) {
+		fmt.Println("ERROR: Keyring import with the file backend reads its passphrase from stdin and can't be used with --mnemonic-file -.")
+		os.Exit(1)
+	}
+
+	// Derive candidates from BIP39 mnemonics instead of raw private keys
+	if *useMnemonic {
+		if *hdPath == "" {
+			*hdPath = defaultHDPath(settings.SelectedChain.CoinType)
+		}
+
+		mnemonicGen, err := newMnemonicGenerator(m.mustGenerator(), *mnemonicWords, *hdPath)
+		if err != nil {
+			fmt.Println("ERROR: " + err.Error())
+			os.Exit(1)
+		}
+
+		m.Generator = mnemonicGen
+	}
+
+	// Walk the HD paths of an existing mnemonic instead of generating random keys
+	if *mnemonicFile != "" {
+		mnemonic, err := readMnemonic(*mnemonicFile)
+		if err != nil {
+			fmt.Println("ERROR: Can't read mnemonic: " + err.Error())
+			os.Exit(1)
+		}
+
+		walkGen, err := newHDWalkGenerator(m.mustGenerator(), mnemonic, settings.SelectedChain.CoinType, *hdAccounts)
+		if err != nil {
+			fmt.Println("ERROR: " + err.Error())
+			os.Exit(1)
+		}
+
+		m.Generator = walkGen
+	}
+
+	// Search partial private keys for a split-key request
+	if *splitKey != "" {
+		publicKey, err := hex.DecodeString(*splitKey)
+		if err != nil {
+			fmt.Println("ERROR: Invalid split-key public key: " + err.Error())
+			os.Exit(1)
+		}
+
+		splitGen, err := newSplitKeyGenerator(m.mustGenerator(), publicKey)
+		if err != nil {
+			fmt.Println("ERROR: " + err.Error())
+			os.Exit(1)
+		}
+
+		m.Generator = splitGen
+	}
+
+	// Export EVM keys into encrypted keystore files
+	var keystoreExport keystoreExporter
+
+	if *keystoreDir != "" {
+		if !keystoreSupported(settings.SelectedChain) {
+			fmt.Println("ERROR: Keystore export is only supported for EVM keys (ECSDA and Ethsecp256k1 chains).")
+			os.Exit(1)
+		}
+
+		if *splitKey != "" {
+			fmt.Println("ERROR: Keystore export can't be used with --split-key, partial keys must be combined first.")
+			os.Exit(1)
+		}
+
+		var passphrase string
+		var err error
+		if *keystorePasswordFD >= 0 {
+			passphrase, err = readPassphraseFD(*keystorePasswordFD)
+		} else {
+			passphrase, err = promptPassphrase("Keystore passphrase")
+		}
+		if err != nil {
+			fmt.Println("ERROR: Can't read keystore passphrase: " + err.Error())
+			os.Exit(1)
+		}
+
+		keystoreExport, err = newKeystoreExporter(*keystoreDir, passphrase, keystore.StandardScryptN, keystore.StandardScryptP)
+		if err != nil {
+			fmt.Println("ERROR: " + err.Error())
+			os.Exit(1)
+		}
+	}
+
+	// Import Cosmos keys into a cosmos-sdk keyring
+	var keyringImport keyringImporter
+
+	if *keyringDir != "" {
+		if !keyringSupported(settings.SelectedChain) {
+			fmt.Println("ERROR: Keyring import is only supported for Secp256k1 chains.")
+			os.Exit(1)
+		}
+
+		if *splitKey != "" {
+			fmt.Println("ERROR: Keyring import can't be used with --split-key, partial keys must be combined first.")
+			os.Exit(1)
+		}
+
+		var err error
+		keyringImport, err = newKeyringImporter(*keyringBackend, *keyringDir, *keyringKeyName, os.Stdin)
+		if err != nil {
+			fmt.Println("ERROR: " + err.Error())
+			os.Exit(1)
+		}
+	}
+
+	var foundWallets []wallet
 	NumAccountsInt, err := strconv.Atoi(*&settings.NumAccounts)
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
 
+	// Fail before the search if the keyring can't be read or a key name is taken
+	if *keyringDir != "" {
+		if err := keyringImport.CheckKeyNames(NumAccountsInt); err != nil {
+			fmt.Println("ERROR: Can't import into keyring: " + err.Error() + ".")
+			os.Exit(1)
+		}
+	}
+
+	// Open the output for found wallets. Machine readable output on stdout is kept parseable
+	// by sending messages and progress to stderr.
+	var output io.Writer = os.Stdout
+	var messages = os.Stdout
+	var outputFile *os.File
+
+	if *outFile != "" {
+		outputFile, err = os.OpenFile(*outFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		if err != nil {
+			fmt.Println("ERROR: Can't open output file: " + err.Error())
+			os.Exit(1)
+		}
+		output = outputFile
+	} else if *outputFormat != "text" {
+		messages = os.Stderr
+	}
+
+	results, err := newResultWriter(*outputFormat, output)
+	if err != nil {
+		fmt.Println("ERROR: " + err.Error())
+		os.Exit(1)
+	}
+
 	// Print out settings
 	if *verbose == true {
-		fmt.Println("Matcher Mode: " + *&settings.MatcherMode)
-		fmt.Println("Search String: " + *&settings.SearchString)
-		fmt.Println("Number of Accounts to Generate: " + *&settings.NumAccounts)
-		fmt.Println("Selected Chain: ")
-		fmt.Println("  Name: " + *&settings.SelectedChain.Name)
-		fmt.Println("  Prefix: " + *&settings.SelectedChain.Prefix)
-		fmt.Println("  Encryption: ")
+		if len(patterns) > 0 {
+			fmt.Fprintln(messages, "Patterns: ")
+			for _, p := range patterns {
+				fmt.Fprintf(messages, "  %s %s (%d)\n", p.Mode, p.Search, p.Count)
+			}
+		} else if *top > 0 {
+			fmt.Fprintln(messages, "Scoring: top "+strconv.Itoa(*top)+" by "+scoring.weights.String())
+			if *searchString != "" {
+				fmt.Fprintln(messages, "Search String: "+*&settings.SearchString)
+			}
+		} else if len(conditions) > 0 {
+			fmt.Fprintln(messages, "Conditions: ")
+			for _, c := range conditions {
+				fmt.Fprintln(messages, "  "+c.String())
+			}
+		} else {
+			fmt.Fprintln(messages, "Matcher Mode: "+*&settings.MatcherMode)
+			fmt.Fprintln(messages, "Search String: "+*&settings.SearchString)
+		}
+		if settings.MatcherMode == wordsMode {
+			if wordSet, err := m.words(); err == nil {
+				fmt.Fprintf(messages, "Words: %d of %d words of the wordlist can appear in addresses\n", wordSet.Len(), len(words))
+			}
+		}
+		if *maskFlag != "" {
+			fmt.Fprintln(messages, "Mask: "+addressMask.String())
+		}
+		if len(constraints) > 0 {
+			fmt.Fprintln(messages, "Constraints: ")
+			for _, c := range constraints {
+				fmt.Fprintln(messages, "  "+c.String())
+			}
+		}
+		if caseSensitive {
+			fmt.Fprintln(messages, "Case Matching: checksum, each cased letter of the search doubles the expected work")
+			if letters := m.casedLetters(); letters > 0 {
+				fmt.Fprintf(messages, "  %d cased letters, %s times the work of a case-insensitive search\n", letters, formatCount(math.Pow(2, float64(letters))))
+			}
+		} else {
+			fmt.Fprintln(messages, "Case Matching: insensitive")
+		}
+		fmt.Fprintln(messages, "Number of Accounts to Generate: "+*&settings.NumAccounts)
+		fmt.Fprintln(messages, "Threads: "+strconv.Itoa(*threads))
+		fmt.Fprintln(messages, "Selected Chain: ")
+		fmt.Fprintln(messages, "  Name: "+*&settings.SelectedChain.Name)
+		fmt.Fprintln(messages, "  Prefix: "+*&settings.SelectedChain.Prefix)
+		fmt.Fprintln(messages, "  Coin Type: "+strconv.FormatUint(uint64(settings.SelectedChain.CoinType), 10))
+		fmt.Fprintln(messages, "  Encryption: ")
+		fmt.Fprintln(messages, "    "+settings.SelectedChain.Encryption.String())
+
+		if *useMnemonic {
+			fmt.Fprintln(messages, "Mnemonic Words: "+strconv.Itoa(*mnemonicWords))
+			fmt.Fprintln(messages, "HD Path: "+*hdPath)
+		}
+
+		if *mnemonicFile != "" {
+			fmt.Fprintln(messages, "HD Accounts: "+strconv.FormatUint(uint64(*hdAccounts), 10))
+		}
+
+		if *splitKey != "" {
+			fmt.Fprintln(messages, "Split-key Public Key: "+*splitKey)
+		}
+	}
+
+	// Estimate the difficulty, benchmarking only searches that may take a while.
+	// A budget already bounds the search, so it isn't spent on the benchmark.
+	d, err := m.Difficulty()
+	if err == nil && *top == 0 {
+		budgeted := *maxDuration > 0 || *maxAttempts > 0
+		if d.Probability > 0 && d.ExpectedAttempts() >= estimateBenchmarkAttempts && !budgeted {
+			d.Rate = measureRate(m, *threads, time.Second)
+		}
+
+		if *verbose == true && d.Probability > 0 {
+			fmt.Fprintln(messages, d)
+		}
+
+		if warning := searchWarning(d, NumAccountsInt); warning != "" {
+			fmt.Fprintln(messages, warning)
+		}
+	}
+
+	var attempts atomic.Uint64
+	prog := newProgress(&attempts, NumAccountsInt, d)
+
+	var board *leaderboard
+	if *top > 0 {
+		board = newLeaderboard(*top)
+		prog.ranked = func() string {
+			best, ranked := board.Best()
+			return fmt.Sprintf("%d/%d ranked, best score %g", ranked, *top, best)
+		}
+	}
 
-		switch *&settings.SelectedChain.Encryption {
-		case Secp256k1:
-			fmt.Println("    Secp256k1")
-		case Ethsecp256k1:
-			fmt.Println("    Ethsecp256k1")
-		case ECSDA:
-			fmt.Println("    ECSDA")
+	// Stop the search on SIGINT and SIGTERM, matches found so far are still written
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	// handleMatch writes and exports the ith found wallet
+	handleMatch := func(i int, match searchMatch) {
+		matchingWallet := match.Wallet
+		foundWallets = append(foundWallets, matchingWallet)
+		prog.Found()
+
+		// Keys exported to a keystore file are only written encrypted
+		written := matchingWallet
+		var keystorePath string
+		if *keystoreDir != "" {
+			path, err := keystoreExport.Export(matchingWallet)
+			if err != nil {
+				fmt.Fprintln(messages, "ERROR: Can't write keystore file: "+err.Error())
+			} else {
+				keystorePath = path
+				written.PrivateKey = nil
+				written.Mnemonic = ""
+			}
+		}
+
+		err := results.Write(result{
+			Wallet:   written,
+			Index:    i + 1,
+			Total:    NumAccountsInt,
+			Chain:    settings.SelectedChain,
+			Mode:     match.Pattern.Mode,
+			Pattern:  match.Pattern.Search,
+			Score:    match.Score,
+			Ranked:   board != nil,
+			Word:     match.Word,
+			Attempts: attempts.Load(),
+			Elapsed:  prog.Elapsed(),
+		})
+		if err != nil {
+			fmt.Fprintln(messages, "ERROR: Can't write result: "+err.Error())
 		}
 
+		if outputFile != nil {
+			fmt.Fprintf(messages, "\nFound a new matching wallet (%d out of %d): %s\n", i+1, NumAccountsInt, matchingWallet.Address)
+		}
+
+		if keystorePath != "" {
+			fmt.Fprintln(messages, "Keystore file:\t"+keystorePath)
+		}
 	}
 
+	var searchErr error
+	var bestMiss nearMiss
+	var hasBestMiss bool
+
 	action := func() {
-		for i := 0; i < NumAccountsInt; i++ {
-			// TODO limit CPU cores by flag
-			matchingWallet = findMatchingWalletConcurrent(m, runtime.NumCPU())
+		// Scoring runs last until the budget ends them, then the leaderboard is written in order
+		if board != nil {
+			pool := startScoringPool(ctx, m, scoring, board, *threads, &attempts, searchBudget{MaxDuration: *maxDuration, MaxAttempts: *maxAttempts})
+			_, searchErr = pool.Next(ctx)
+			pool.Stop()
+
+			ranked := pattern{Mode: scoreMode, Search: scoring.weights.String()}
+			for i, entry := range board.Ranked() {
+				handleMatch(i, searchMatch{Wallet: entry.Wallet, Pattern: ranked, Score: entry.Score})
+			}
+			return
+		}
+
+		pool := startSearchPool(ctx, m, NumAccountsInt, *threads, &attempts, searchBudget{MaxDuration: *maxDuration, MaxAttempts: *maxAttempts})
+
+		for len(foundWallets) < NumAccountsInt {
+			match, err := pool.Next(ctx)
+			if err != nil {
+				searchErr = err
+				break
+			}
+			handleMatch(len(foundWallets), match)
+		}
 
-			fmt.Printf("\nFound a new matching wallet (%d out of %d):\n", i+1, NumAccountsInt)
-			fmt.Println(matchingWallet)
+		// Matches found by other goroutines while the last requested one was handled,
+		// or before the search was interrupted
+		extra := pool.Stop()
+		for len(extra) > 0 && len(foundWallets) < NumAccountsInt {
+			handleMatch(len(foundWallets), extra[0])
+			extra = extra[1:]
+		}
+
+		if surplus := len(extra) + pool.Surplus(); surplus > 0 && *verbose == true {
+			fmt.Fprintf(messages, "\n%d more matching wallets were found and not written, use -n to request more accounts\n", surplus)
+		}
+
+		bestMiss, hasBestMiss = pool.BestMiss()
+	}
+
+	prog.Start()
+	runWithProgress(action, cancel, prog, messages)
+
+	// Restore the default signal handling, so prompts below can be interrupted again
+	interrupted := ctx.Err() != nil
+	cancel()
+
+	// Scoring runs are complete with the wallets ranked when the budget ends them
+	incomplete := board == nil && len(foundWallets) < NumAccountsInt
+	budgetExhausted := !interrupted && incomplete && (errors.Is(searchErr, errMaxDuration) || errors.Is(searchErr, errMaxAttempts))
+
+	var stopReason string
+	if interrupted {
+		stopReason = "Search interrupted."
+	} else if budgetExhausted {
+		stopReason = "Search stopped, " + searchErr.Error() + "."
+	}
+
+	if stopReason != "" || *verbose == true {
+		fmt.Fprintln(messages, "\n"+prog.Summary(stopReason))
+	}
+
+	if incomplete && hasBestMiss {
+		if slices.Contains(ZeroModes, bestMiss.Pattern.Mode) {
+			fmt.Fprintf(messages, "Best near-miss:\t%s (%d of %d %s)\n", bestMiss.Wallet.Address, bestMiss.Closeness, bestMiss.Length, zeroModeUnit(bestMiss.Pattern.Mode))
+		} else if slices.Contains(StructureModes, bestMiss.Pattern.Mode) {
+			fmt.Fprintf(messages, "Best near-miss:\t%s (%d of %d %s)\n", bestMiss.Wallet.Address, bestMiss.Closeness, bestMiss.Length, structureModeUnit(bestMiss.Pattern.Mode))
+		} else {
+			fmt.Fprintf(messages, "Best near-miss:\t%s (%d of %d characters of %s)\n", bestMiss.Wallet.Address, bestMiss.Closeness, bestMiss.Length, bestMiss.Pattern.Search)
 		}
 	}
 
-	spinerr := spinner.New().
-		Type(spinner.Meter).
-		Action(action).
-		Title(" Generating accounts...").
-		Run()
+	if err := results.Close(); err != nil {
+		fmt.Fprintln(messages, "ERROR: Can't write results: "+err.Error())
+	}
+
+	if outputFile != nil {
+		if err := outputFile.Close(); err != nil {
+			fmt.Fprintln(messages, "ERROR: Can't write output file: "+err.Error())
+		}
+	}
+
+	// Import found keys after the spinner, the file backend asked for its passphrase before the search
+	if *keyringDir != "" {
+		var armorPassphrase string
+		if *keyringExportArmor {
+			armorPassphrase, err = promptPassphrase("Armor passphrase")
+			if err != nil {
+				fmt.Fprintln(messages, "ERROR: Can't read armor passphrase: "+err.Error())
+				os.Exit(1)
+			}
+		}
 
-	if spinerr != nil {
-		fmt.Println(spinerr)
+		for i, w := range foundWallets {
+			uid := keyringImport.UID(i, NumAccountsInt)
+			if err := keyringImport.Import(uid, w); err != nil {
+				fmt.Fprintln(messages, "ERROR: Can't import "+w.Address+" into keyring: "+err.Error())
+				continue
+			}
+			fmt.Fprintln(messages, "\nImported "+w.Address+" into keyring as "+uid)
+
+			if *keyringExportArmor {
+				armor, err := keyringImport.ExportArmor(uid, armorPassphrase)
+				if err != nil {
+					fmt.Fprintln(messages, "ERROR: Can't export "+uid+": "+err.Error())
+					continue
+				}
+				fmt.Fprintln(messages, armor)
+			}
+		}
+	}
+
+	if interrupted {
+		os.Exit(exitInterrupted)
+	}
+	if budgetExhausted {
+		os.Exit(exitBudgetExhausted)
 	}
 }
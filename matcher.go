@@ -1,29 +1,160 @@
 package main
 
 import (
-	"regexp"
+	"context"
+	"encoding/hex"
+	"errors"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/exp/slices"
 )
 
-// MatchWithMode matches the candidate string with the specified mode in the matcher.
+// MatchWithMode matches the candidate string with the specified mode in the matcher,
+// or with any of its patterns if set.
 // It returns true if the candidate matches the mode, otherwise false.
 func (m matcher) MatchWithMode(candidate string) bool {
-	switch m.Mode {
-	case "contains":
-		return strings.Contains(candidate, m.SearchString)
-	case "starts-with":
-		return strings.HasPrefix(candidate, m.SearchString)
-	case "ends-with":
-		return strings.HasSuffix(candidate, m.SearchString)
-	case "regex":
-		match, err := regexp.MatchString(m.SearchString, candidate)
+	if len(m.Patterns) > 0 {
+		set, err := m.compiledPatterns()
+		return err == nil && len(set.Match(candidate)) > 0
+	}
+
+	match, err := m.compiled()
+	if err != nil {
+		return false
+	}
+	return match(candidate)
+}
+
+// compiled returns the compiled predicate if set, otherwise it compiles the search.
+func (m matcher) compiled() (predicate, error) {
+	if m.predicate != nil {
+		return m.predicate, nil
+	}
+	return m.compile()
+}
+
+// compiledPatterns returns the compiled patterns if set, otherwise it compiles the patterns.
+func (m matcher) compiledPatterns() (*patternSet, error) {
+	if m.patternSet != nil {
+		return m.patternSet, nil
+	}
+	return compilePatterns(m.Patterns)
+}
+
+// prepare returns the matcher with its generator resolved and its search compiled,
+// so they are not looked up for every generated key.
+// Callers are expected to have run ValidateInput first.
+func (m matcher) prepare() matcher {
+	m.Generator = m.mustGenerator()
+
+	if len(m.Patterns) > 0 {
+		set, err := m.compiledPatterns()
 		if err != nil {
-			return false
+			panic(err)
 		}
-		return match
-	default:
-		return strings.Contains(candidate, m.SearchString)
+		m.patternSet = set
+		return m
+	}
+
+	if m.Mode == wordsMode {
+		words, err := m.words()
+		if err != nil {
+			panic(err)
+		}
+		m.wordSet = words
+	}
+
+	match, err := m.compiled()
+	if err != nil {
+		panic(err)
+	}
+	m.predicate = match
+
+	return m
+}
+
+// words returns the filtered wordlist if set, otherwise it filters the wordlist to the words
+// of at least the length of the search string that can appear in addresses.
+func (m matcher) words() (*wordSet, error) {
+	if m.wordSet != nil {
+		return m.wordSet, nil
+	}
+
+	g, err := m.generator()
+	if err != nil {
+		return nil, err
+	}
+	minLength, err := strconv.Atoi(m.SearchString)
+	if err != nil {
+		return nil, err
 	}
+	return newWordSet(m.Words, m.alphabet(g), minLength, g.AddressLength(), m.Leetspeak), nil
+}
+
+// FindWord returns the longest word of the words mode in the candidate and where it was found.
+// It returns false in other modes, or if the candidate contains no word.
+func (m matcher) FindWord(candidate string) (wordMatch, bool) {
+	if m.Mode != wordsMode {
+		return wordMatch{}, false
+	}
+
+	words, err := m.words()
+	if err != nil {
+		return wordMatch{}, false
+	}
+	return words.Find(m.trim(candidate))
+}
+
+// patterns returns the patterns of the matcher, or its mode and search string as a single
+// pattern without a count. Conditions are a single pattern described by describeConditions.
+func (m matcher) patterns() []pattern {
+	if len(m.Patterns) > 0 {
+		return m.Patterns
+	}
+	if len(m.Conditions) > 0 {
+		return []pattern{{Mode: conditionsMode, Search: describeConditions(m.Conditions)}}
+	}
+	return []pattern{{Mode: m.Mode, Search: m.SearchString}}
+}
+
+// searchLength returns the number of characters the Closeness of a match would be,
+// or the minimum score of zero and structure modes and the minimum word length of the words mode.
+func (m matcher) searchLength() int {
+	if slices.Contains(ZeroModes, m.Mode) || slices.Contains(StructureModes, m.Mode) || m.Mode == wordsMode {
+		minScore, _ := strconv.Atoi(m.SearchString)
+		return minScore
+	}
+	if len(m.Conditions) == 0 {
+		return len(m.SearchString)
+	}
+
+	n := 0
+	for _, c := range m.Conditions {
+		if c.Mode != "not-contains" {
+			n += len(c.Search)
+		}
+	}
+	return n
+}
+
+// forCondition returns a matcher searching only for c.
+func (m matcher) forCondition(c condition) matcher {
+	m.Conditions = nil
+	return m.forPattern(pattern{Mode: c.Mode, Search: c.Search})
+}
+
+// forPattern returns a matcher searching only for p.
+func (m matcher) forPattern(p pattern) matcher {
+	m.Mode = p.Mode
+	m.SearchString = p.Search
+	m.Patterns = nil
+	m.predicate = nil
+	m.patternSet = nil
+	return m
 }
 
 // Match checks if the candidate string matches the criteria specified in the matcher.
@@ -31,173 +162,685 @@ func (m matcher) MatchWithMode(candidate string) bool {
 // and then calls MatchWithMode to perform the matching based on the mode.
 // It returns true if the candidate matches the criteria, otherwise false.
 func (m matcher) Match(candidate string) bool {
-	candidate = strings.TrimPrefix(candidate, m.Chain.PrefixFull)
+	candidate, ok := m.body(candidate)
+	if !ok {
+		return false
+	}
+
+	return m.MatchWithMode(candidate)
+}
+
+// MatchWallet checks the address of a generated wallet like MatchPatterns. Zero modes
+// score the raw address bytes of the wallet instead of decoding its hex address, and only
+// check the mask and count constraints of the address when the score is high enough.
+func (m matcher) MatchWallet(w wallet) []int {
+	if len(m.Patterns) > 0 || w.AddressBytes == nil || !slices.Contains(ZeroModes, m.Mode) {
+		return m.MatchPatterns(w.Address)
+	}
+
+	if zeroScore(m.Mode, w.AddressBytes) < m.searchLength() {
+		return nil
+	}
+	if _, ok := m.body(w.Address); !ok {
+		return nil
+	}
+	return []int{0}
+}
+
+// Score returns the score of a wallet in a zero or structure mode, and 0 in the other modes.
+func (m matcher) Score(w wallet) int {
+	if slices.Contains(StructureModes, m.Mode) {
+		return structureScore(m.Mode, m.trim(w.Address))
+	}
+	if !slices.Contains(ZeroModes, m.Mode) {
+		return 0
+	}
+	if w.AddressBytes == nil {
+		w.AddressBytes, _ = hex.DecodeString(m.trim(w.Address))
+	}
+	return zeroScore(m.Mode, w.AddressBytes)
+}
+
+// MatchPatterns checks the candidate like Match, and returns the indices of the matched
+// patterns in ascending order, or nil if it doesn't match. Without Patterns a match is pattern 0.
+func (m matcher) MatchPatterns(candidate string) []int {
+	if len(m.Patterns) == 0 {
+		if m.Match(candidate) {
+			return []int{0}
+		}
+		return nil
+	}
+
+	candidate, ok := m.body(candidate)
+	if !ok {
+		return nil
+	}
+
+	set, err := m.compiledPatterns()
+	if err != nil {
+		return nil
+	}
+	return set.Match(candidate)
+}
+
+// body trims the prefix from the candidate, and checks the required amount of digits and letters.
+// It returns the trimmed candidate and true if it contains the required characters, otherwise false.
+func (m matcher) body(candidate string) (string, bool) {
+	candidate = m.trim(candidate)
 
 	if !m.CheckRequiredDigits(candidate, m.RequiredDigits) {
-		return false
+		return candidate, false
 	}
 
 	if !m.CheckRequiredLetters(candidate, m.RequiredLetters) {
-		return false
+		return candidate, false
 	}
 
-	return m.MatchWithMode(candidate)
+	if !m.CheckConstraints(candidate) {
+		return candidate, false
+	}
+
+	return candidate, true
 }
 
-// ValidateInput validates the input parameters of the matcher and returns any validation errors.
-// It dynamically selects the appropriate generator based on the encryption type in the chain,
-// and then calls the generator's ValidateInput method.
-// It returns a slice of validation error messages.
-func (m matcher) ValidateInput() []string {
-	var generatorValidate func(SearchString string, RequiredLetters, RequiredDigits int) []string
+// CheckConstraints checks if the candidate matches the mask and the count constraints.
+// It returns true if it matches all of them, otherwise false.
+func (m matcher) CheckConstraints(candidate string) bool {
+	if m.Mask.Empty() && len(m.Constraints) == 0 {
+		return true
+	}
+
+	g := m.mustGenerator()
+	if !m.Mask.Match(candidate, g.Digits(), g.Letters()) {
+		return false
+	}
 
-	switch m.Chain.Encryption {
-	case Secp256k1:
-		var secp256k1generator = secp256k1Wallet{
-			Chain: m.Chain,
+	for _, c := range m.Constraints {
+		chars := g.Digits()
+		if c.Class == "letters" {
+			chars = g.Letters()
 		}
+		if !c.Check(candidate, chars) {
+			return false
+		}
+	}
+	return true
+}
 
-		generatorValidate = secp256k1generator.ValidateInput
-	case ECSDA:
-		var ecsdagenerator = ecsdaWallet{
-			Chain: m.Chain,
+// trim trims the prefix from the candidate and lowercases it, unless the matcher is case sensitive.
+// Addresses that are lowercase already, like bech32 addresses, are returned without a copy.
+func (m matcher) trim(candidate string) string {
+	candidate = strings.TrimPrefix(candidate, m.Chain.PrefixFull)
+	if !m.CaseSensitive {
+		candidate = strings.ToLower(candidate)
+	}
+	return candidate
+}
+
+// alphabet returns the characters a search can match, the generator's alphabet lowercased
+// unless the matcher is case sensitive.
+func (m matcher) alphabet(g Generator) string {
+	if !m.CaseSensitive {
+		return strings.ToLower(g.Alphabet())
+	}
+	return g.Alphabet()
+}
+
+// casedLetters returns the number of letters of the search string, or of the conditions that
+// have to appear in an address, whose case has to match the checksum. Each of them halves the
+// probability of a match. It returns 0 unless the matcher is case sensitive.
+func (m matcher) casedLetters() int {
+	if !m.CaseSensitive || m.Mode == "regex" {
+		return 0
+	}
+
+	search := m.SearchString
+	for _, c := range m.Conditions {
+		if c.Mode != "not-contains" {
+			search += c.Search
 		}
+	}
 
-		generatorValidate = ecsdagenerator.ValidateInput
-	default:
-		var secp256k1generator = secp256k1Wallet{
-			Chain: m.Chain,
+	letters := m.mustGenerator().Letters()
+	n := 0
+	for _, char := range search {
+		if strings.ContainsRune(letters, char) {
+			n++
 		}
-		generatorValidate = secp256k1generator.ValidateInput
 	}
+	return n
+}
 
-	return generatorValidate(m.SearchString, m.RequiredLetters, m.RequiredDigits)
+// generator returns the generator used by the matcher.
+// It returns the resolved Generator if set, otherwise it looks up the generator
+// registered for the encryption type in the chain.
+func (m matcher) generator() (Generator, error) {
+	if m.Generator != nil {
+		return m.Generator, nil
+	}
+	return NewGenerator(m.Chain)
 }
 
-// CheckRequiredDigits checks if the candidate string contains the required amount of digits.
-// It dynamically selects the appropriate generator based on the encryption type in the chain,
-// and then calls the generator's CheckRequiredDigits method.
-// It returns true if the candidate contains the required amount of digits, otherwise false.
-func (m matcher) CheckRequiredDigits(candidate string, required int) bool {
-	var gcrd func(candidate string, required int) bool
+// mustGenerator returns the generator used by the matcher and panics if there is none.
+// Callers are expected to have run ValidateInput first.
+func (m matcher) mustGenerator() Generator {
+	g, err := m.generator()
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// ValidateInput validates the input parameters of the matcher and returns any validation errors.
+// It validates the search with validateSearch and the mask and count constraints with validateConstraints.
+func (m matcher) ValidateInput() []string {
+	errs := m.validateSearch()
+	return append(errs, m.validateConstraints()...)
+}
+
+// validateConstraints checks that the mask and the regions and counts of the constraints
+// fit in the addresses of the generator. It returns a slice of validation error messages.
+func (m matcher) validateConstraints() []string {
+	g, err := m.generator()
+	if err != nil {
+		return nil
+	}
+
+	var errs []string
+	length := g.AddressLength()
+	if len(m.Mask.Head)+len(m.Mask.Tail) > length {
+		errs = append(errs, "ERROR: Mask "+m.Mask.String()+" is longer than addresses of "+strconv.Itoa(length)+" characters.")
+	}
+	for _, c := range m.Constraints {
+		errs = append(errs, c.validate(length)...)
+	}
+	return errs
+}
+
+// validateSearch validates the search of the matcher and returns any validation errors.
+// It resolves the generator registered for the encryption type in the chain,
+// and then calls the generator's ValidateInput method.
+// A regex search string is validated as a regex matching the generator's alphabet instead.
+// Every pattern is validated on its own, with errors naming the pattern.
+// Checksum case matching is only valid for EVM chains, whose addresses are mixed case.
+// It returns a slice of validation error messages.
+func (m matcher) validateSearch() []string {
+	g, err := m.generator()
+	if err != nil {
+		return []string{"ERROR: " + err.Error() + "."}
+	}
+
+	// Only EVM addresses are mixed case, bech32 addresses are generated in lowercase
+	if m.CaseSensitive && m.Chain.Encryption != ECSDA {
+		return []string{"ERROR: Checksum case matching is only supported for EVM chains with 0x addresses."}
+	}
 
-	switch m.Chain.Encryption {
-	case Secp256k1:
-		var secp256k1generator = secp256k1Wallet{
-			Chain: m.Chain,
+	if len(m.Patterns) > 0 {
+		var errs []string
+		for _, p := range m.Patterns {
+			if p.Mode == wordsMode {
+				errs = append(errs, "ERROR: Pattern "+p.Mode+" "+p.Search+": the words mode can't be used in a patterns file.")
+				continue
+			}
+			for _, err := range m.forPattern(p).validateSearch() {
+				errs = append(errs, "ERROR: Pattern "+p.Mode+" "+p.Search+": "+strings.TrimPrefix(err, "ERROR: "))
+			}
 		}
+		return errs
+	}
 
-		gcrd = secp256k1generator.CheckRequiredDigits
-	case ECSDA:
-		var ecsdagenerator = ecsdaWallet{
-			Chain: m.Chain,
+	if len(m.Conditions) > 0 {
+		errs := g.ValidateInput("", m.RequiredLetters, m.RequiredDigits)
+		for _, c := range m.Conditions {
+			errs = append(errs, g.ValidateInput(c.Search, 0, 0)...)
 		}
+		return append(errs, validateConditions(m.Conditions, g.AddressLength())...)
+	}
 
-		gcrd = ecsdagenerator.CheckRequiredDigits
-	default:
-		var secp256k1generator = secp256k1Wallet{
-			Chain: m.Chain,
+	if slices.Contains(ZeroModes, m.Mode) {
+		if m.Chain.Encryption != ECSDA {
+			return []string{"ERROR: " + m.Mode + " is only supported for EVM chains with 0x addresses."}
+		}
+		if m.RequiredLetters != 0 || m.RequiredDigits != 0 {
+			return []string{"ERROR: " + m.Mode + " can't be combined with required letters or digits."}
+		}
+		return validateZeros(m.Mode, m.SearchString, g.AddressLength())
+	}
+
+	if slices.Contains(StructureModes, m.Mode) {
+		// The estimate and the shapes assume lowercase addresses
+		if m.CaseSensitive {
+			return []string{"ERROR: " + m.Mode + " can't be combined with checksum case matching."}
+		}
+		errs := g.ValidateInput("", m.RequiredLetters, m.RequiredDigits)
+		return append(errs, validateStructure(m.Mode, m.SearchString, m.alphabet(g), g.AddressLength())...)
+	}
+
+	if m.Mode == wordsMode {
+		errs := g.ValidateInput("", m.RequiredLetters, m.RequiredDigits)
+		if minLength, err := strconv.Atoi(m.SearchString); err != nil || minLength < 1 || minLength > g.AddressLength() {
+			return append(errs, "ERROR: The search string of words must be the minimum word length, between 1 and "+strconv.Itoa(g.AddressLength())+".")
+		}
+		if len(m.Words) == 0 {
+			return append(errs, "ERROR: The words mode needs a wordlist.")
 		}
-		gcrd = secp256k1generator.CheckRequiredDigits
+		if words, _ := m.words(); words.Len() == 0 {
+			errs = append(errs, "ERROR: No word of the wordlist with at least "+m.SearchString+" characters can appear in addresses of this chain.")
+		}
+		return errs
+	}
+
+	if m.Mode == "regex" {
+		errs := g.ValidateInput("", m.RequiredLetters, m.RequiredDigits)
+		return append(errs, validateRegex(m.SearchString, m.alphabet(g))...)
 	}
 
-	return gcrd(candidate, required)
+	return g.ValidateInput(m.SearchString, m.RequiredLetters, m.RequiredDigits)
+}
+
+// CheckRequiredDigits checks if the candidate string contains the required amount of digits.
+// It returns true if the candidate contains the required amount of digits, otherwise false.
+func (m matcher) CheckRequiredDigits(candidate string, required int) bool {
+	return m.mustGenerator().CheckRequiredDigits(candidate, required)
 }
 
 // CheckRequiredLetters checks if the candidate string contains the required amount of letters.
-// It dynamically selects the appropriate generator based on the encryption type in the chain,
-// and then calls the generator's CheckRequiredLetters method.
 // It returns true if the candidate contains the required amount of letters, otherwise false.
 func (m matcher) CheckRequiredLetters(candidate string, required int) bool {
-	var gcrl func(candidate string, required int) bool
+	return m.mustGenerator().CheckRequiredLetters(candidate, required)
+}
 
-	switch m.Chain.Encryption {
-	case Secp256k1:
-		var secp256k1generator = secp256k1Wallet{
-			Chain: m.Chain,
-		}
+// GenerateWallet generates a wallet using the generator registered for the encryption type in the chain.
+// It returns the generated wallet.
+func (m matcher) GenerateWallet() wallet {
+	return m.mustGenerator().GenerateWallet()
+}
+
+// Closeness returns how many characters of the search string the candidate matches, the length
+// of the longest matching prefix of the search string for contains. It is used to report the best
+// near-miss of searches that end without a match. Regex and words searches have no closeness,
+// almost every address contains a word shorter than the minimum length of the words mode.
+// The closeness of zero and structure modes is their score.
+// The closeness of conditions is the sum of the closeness of the conditions other than not-contains.
+func (m matcher) Closeness(candidate string) int {
+	return m.closeness(m.trim(candidate))
+}
 
-		gcrl = secp256k1generator.CheckRequiredLetters
-	case ECSDA:
-		var ecsdagenerator = ecsdaWallet{
-			Chain: m.Chain,
+// closeness returns the Closeness of a candidate trimmed by trim.
+func (m matcher) closeness(candidate string) int {
+	if len(m.Conditions) > 0 {
+		n := 0
+		for _, c := range m.Conditions {
+			if c.Mode != "not-contains" {
+				n += m.forCondition(c).closeness(candidate)
+			}
 		}
+		return n
+	}
+
+	search := m.SearchString
 
-		gcrl = ecsdagenerator.CheckRequiredLetters
+	n := 0
+	switch m.Mode {
+	case "starts-with":
+		for n < len(search) && n < len(candidate) && candidate[n] == search[n] {
+			n++
+		}
+	case "ends-with":
+		for n < len(search) && n < len(candidate) && candidate[len(candidate)-1-n] == search[len(search)-1-n] {
+			n++
+		}
+	case "regex", wordsMode:
+	case "leading-zero-bytes", "zero-nibbles":
+		address, _ := hex.DecodeString(candidate)
+		n = zeroScore(m.Mode, address)
+	case "runs", "repeats", "palindromes", "sequences":
+		n = structureScore(m.Mode, candidate)
 	default:
-		var secp256k1generator = secp256k1Wallet{
-			Chain: m.Chain,
+		for n < len(search) && strings.Contains(candidate, search[:n+1]) {
+			n++
 		}
-		gcrl = secp256k1generator.CheckRequiredLetters
 	}
+	return n
+}
 
-	return gcrl(candidate, required)
+// errMaxDuration and errMaxAttempts are the causes of searches stopped by their searchBudget.
+var (
+	errMaxDuration = errors.New("maximum duration reached")
+	errMaxAttempts = errors.New("maximum attempts reached")
+)
+
+// searchBudget limits a search, zero values are unlimited.
+type searchBudget struct {
+	MaxDuration time.Duration
+	MaxAttempts uint64
 }
 
-// GenerateWallet generates a wallet based on the encryption type in the chain.
-// It dynamically selects the appropriate generator based on the encryption type in the chain,
-// and then calls the generator's GenerateWallet method.
-// It returns the generated wallet.
-func (m matcher) GenerateWallet() wallet {
-	var generate func() wallet
+// closest returns the closeness of the candidate to the one of the patterns it comes closest to, and that pattern.
+func (m matcher) closest(candidate string, patterns []pattern) (int, pattern) {
+	var best pattern
+	closeness := 0
 
-	switch m.Chain.Encryption {
-	case Secp256k1:
-		var secp256k1generator = secp256k1Wallet{
-			Chain: m.Chain,
+	candidate = m.trim(candidate)
+	for _, p := range patterns {
+		if n := m.forPattern(p).closeness(candidate); n > closeness {
+			closeness, best = n, p
 		}
+	}
+	return closeness, best
+}
 
-		generate = secp256k1generator.GenerateWallet
-	case ECSDA:
-		var ecsdagenerator = ecsdaWallet{
-			Chain: m.Chain,
-		}
+// nearMiss is the generated wallet that came closest to matching.
+type nearMiss struct {
+	Wallet    wallet
+	Closeness int
+	Length    int     // number of characters a match would have had in common with the pattern
+	Pattern   pattern // pattern the wallet came closest to
+}
 
-		generate = ecsdagenerator.GenerateWallet
-	default:
-		var secp256k1generator = secp256k1Wallet{
-			Chain: m.Chain,
+// searchMatch is a matching wallet together with the pattern it was found for.
+type searchMatch struct {
+	Wallet  wallet
+	Pattern pattern
+	Score   float64   // score of the wallet in zero modes and scoring runs, 0 otherwise
+	Word    wordMatch // word found in the wallet in the words mode
+}
+
+// searchPool is a pool of goroutines searching for matching wallets until it is stopped
+// or its budget is exhausted. The goroutines are started once and shared by all requested accounts.
+// Every match is queued in the order it was found, so no match is lost while the
+// caller is busy with a previous one, and matches of the same address are only queued once.
+// A match is queued for the first pattern it matches whose count isn't reached yet.
+type searchPool struct {
+	m        matcher
+	patterns []pattern
+	attempts *atomic.Uint64
+	budget   searchBudget
+
+	mu        sync.Mutex
+	matches   []searchMatch
+	seen      map[string]bool
+	queued    []int // matches queued per pattern
+	surplus   int   // matches of patterns whose count was already reached
+	best      nearMiss
+	closeness atomic.Int64
+	scorer    scorer       // scores every generated wallet for the board of a scoring run
+	board     *leaderboard // leaderboard of a scoring run, nil when searching for matches
+
+	notify chan struct{}
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	wg     sync.WaitGroup
+}
+
+// newSearchPool returns a searchPool without goroutines. A single search or conditions
+// queues count matches, or every match if count is 0, patterns queue the count of each pattern.
+func newSearchPool(ctx context.Context, m matcher, count int, attempts *atomic.Uint64, budget searchBudget) *searchPool {
+	p := &searchPool{
+		m:        m,
+		patterns: m.patterns(),
+		attempts: attempts,
+		budget:   budget,
+		seen:     make(map[string]bool),
+		notify:   make(chan struct{}, 1),
+	}
+	p.queued = make([]int, len(p.patterns))
+	if len(m.Patterns) == 0 {
+		p.patterns[0].Count = count
+	}
+
+	p.ctx, p.cancel = context.WithCancelCause(ctx)
+	if budget.MaxDuration > 0 {
+		var cancelTimeout context.CancelFunc
+		p.ctx, cancelTimeout = context.WithTimeoutCause(p.ctx, budget.MaxDuration, errMaxDuration)
+
+		cancel := p.cancel
+		p.cancel = func(cause error) {
+			cancel(cause)
+			cancelTimeout()
 		}
-		generate = secp256k1generator.GenerateWallet
 	}
 
-	return generate()
+	return p
+}
+
+// startSearchPool starts the specified number of goroutines, each running the search method.
+// The generator is resolved once and shared by all goroutines. Generated wallets are counted in attempts.
+// The goroutines stop when ctx is done, the budget is exhausted or the pool is stopped.
+// Matches beyond count, see newSearchPool, are only counted as surplus.
+func startSearchPool(ctx context.Context, m matcher, count int, goroutines int, attempts *atomic.Uint64, budget searchBudget) *searchPool {
+	// Resolve the generator and compile the search once instead of once per generated key.
+	m = m.prepare()
+
+	p := newSearchPool(ctx, m, count, attempts, budget)
+	p.start(goroutines)
+	return p
+}
+
+// startScoringPool starts a search pool like startSearchPool, which offers every generated wallet
+// with the required letters and digits to the leaderboard instead of matching it. Next returns
+// the cause of the stop once the run ends, as no matches are queued.
+func startScoringPool(ctx context.Context, m matcher, s scorer, board *leaderboard, goroutines int, attempts *atomic.Uint64, budget searchBudget) *searchPool {
+	m.Generator = m.mustGenerator()
+
+	p := newSearchPool(ctx, m, 0, attempts, budget)
+	p.scorer = s
+	p.board = board
+	p.start(goroutines)
+	return p
+}
+
+// start starts the specified number of goroutines, each running the search method.
+func (p *searchPool) start(goroutines int) {
+	for i := 0; i < goroutines; i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.search()
+		}()
+	}
 }
 
-// findMatchingWallets finds matching wallets based on the matcher criteria and sends them to the channel.
-// It runs in a loop until the quit signal is received.
+// search finds matching wallets based on the matcher criteria and queues them.
+// It runs in a loop until the pool's context is done or the attempt budget is used up.
 // It generates a wallet using the GenerateWallet method and checks if it matches the criteria using the Match method.
-// If a match is found, it sends the wallet to the channel.
-func findMatchingWallets(ch chan wallet, quit chan struct{}, m matcher) {
+// Every generated wallet is counted in attempts.
+func (p *searchPool) search() {
 	for {
 		select {
-		case <-quit:
+		case <-p.ctx.Done():
 			return
 		default:
-			w := m.GenerateWallet()
-			if m.Match(w.Address) {
-				// Do a non-blocking write instead of simple `ch <- w` to prevent
-				// blocking when it's time to quit and ch is full.
-				select {
-				case ch <- w:
-				default:
+			if n := p.attempts.Add(1); p.budget.MaxAttempts > 0 && n > p.budget.MaxAttempts {
+				p.attempts.Add(^uint64(0))
+				p.cancel(errMaxAttempts)
+				return
+			}
+
+			w := p.m.GenerateWallet()
+			if p.board != nil {
+				if candidate, ok := p.m.body(w.Address); ok {
+					p.board.Offer(w, p.scorer.Score(candidate))
 				}
+				continue
+			}
+
+			if matched := p.m.MatchWallet(w); len(matched) > 0 {
+				p.add(w, matched)
+			} else {
+				p.miss(w)
 			}
 		}
 	}
 }
 
-// findMatchingWalletConcurrent finds a matching wallet concurrently using multiple goroutines.
-// It creates a channel for sending and receiving wallets, and a quit channel for signaling the goroutines to stop.
-// It spawns the specified number of goroutines, each running the findMatchingWallets function.
-// It returns the first matching wallet received from the channel.
-func findMatchingWalletConcurrent(m matcher, goroutines int) wallet {
-	ch := make(chan wallet)
-	quit := make(chan struct{})
-	defer close(quit)
+// miss keeps w as the best near-miss if it is closer than the current one.
+func (p *searchPool) miss(w wallet) {
+	if !p.mayBeCloser(w, int(p.closeness.Load())) {
+		return
+	}
 
-	for i := 0; i < goroutines; i++ {
-		go findMatchingWallets(ch, quit, m)
+	closeness, closest := p.m.closest(w.Address, p.patterns)
+	if int64(closeness) <= p.closeness.Load() {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if int64(closeness) > p.closeness.Load() {
+		p.closeness.Store(int64(closeness))
+		p.best = nearMiss{Wallet: w, Closeness: closeness, Length: p.m.forPattern(closest).searchLength(), Pattern: closest}
+	}
+}
+
+// mayBeCloser is a cheap check of a missed wallet before its closeness is computed, so most attempts
+// skip it. It reports whether the wallet may come closer than n to a pattern: literal patterns need the
+// next character of their search string, and zero modes a higher score of the raw address bytes.
+// Regex and words patterns never come closer, and the other patterns and conditions always may.
+func (p *searchPool) mayBeCloser(w wallet, n int) bool {
+	if len(p.m.Conditions) > 0 {
+		return true
+	}
+
+	// Only contains needs the lowercased candidate, prefixes and suffixes are compared in place
+	body := strings.TrimPrefix(w.Address, p.m.Chain.PrefixFull)
+	equal := strings.EqualFold
+	if p.m.CaseSensitive {
+		equal = func(a, b string) bool { return a == b }
+	}
+
+	for _, pt := range p.patterns {
+		search := pt.Search
+		switch pt.Mode {
+		case "starts-with":
+			if n < len(search) && n < len(body) && equal(body[:n+1], search[:n+1]) {
+				return true
+			}
+		case "ends-with":
+			if n < len(search) && n < len(body) && equal(body[len(body)-n-1:], search[len(search)-n-1:]) {
+				return true
+			}
+		case "contains":
+			if n < len(search) && strings.Contains(p.m.trim(w.Address), search[:n+1]) {
+				return true
+			}
+		case "regex", wordsMode:
+		case "leading-zero-bytes", "zero-nibbles":
+			if w.AddressBytes == nil || zeroScore(pt.Mode, w.AddressBytes) > n {
+				return true
+			}
+		default:
+			return true
+		}
 	}
-	return <-ch
+	return false
+}
+
+// BestMiss returns the generated wallet that came closest to matching without matching,
+// or false if no wallet matched any character of the search string.
+func (p *searchPool) BestMiss() (nearMiss, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.best, p.best.Closeness > 0
+}
+
+// add queues a match of the given patterns unless its address was already queued,
+// and wakes up a waiting Next.
+func (p *searchPool) add(w wallet, matched []int) {
+	p.mu.Lock()
+	if p.seen[w.Address] {
+		p.mu.Unlock()
+		return
+	}
+	p.seen[w.Address] = true
+
+	queued := false
+	for _, i := range matched {
+		if p.patterns[i].Count == 0 || p.queued[i] < p.patterns[i].Count {
+			p.queued[i]++
+			m := p.m.forPattern(p.patterns[i])
+			word, _ := m.FindWord(w.Address)
+			p.matches = append(p.matches, searchMatch{Wallet: w, Pattern: p.patterns[i], Score: float64(m.Score(w)), Word: word})
+			queued = true
+			break
+		}
+	}
+	if !queued {
+		p.surplus++
+	}
+	p.mu.Unlock()
+
+	// Do a non-blocking write, a pending notification already wakes up Next.
+	select {
+	case p.notify <- struct{}{}:
+	default:
+	}
+}
+
+// next returns the oldest queued match, or false if there is none.
+func (p *searchPool) next() (searchMatch, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.matches) == 0 {
+		return searchMatch{}, false
+	}
+	match := p.matches[0]
+	p.matches = p.matches[1:]
+	return match, true
+}
+
+// Next waits for the next match.
+// It returns the context's error if ctx is done before a match is found,
+// and errMaxDuration or errMaxAttempts if the budget is exhausted.
+func (p *searchPool) Next(ctx context.Context) (searchMatch, error) {
+	for {
+		if match, ok := p.next(); ok {
+			return match, nil
+		}
+
+		select {
+		case <-p.notify:
+		case <-ctx.Done():
+			return searchMatch{}, ctx.Err()
+		case <-p.ctx.Done():
+			return searchMatch{}, context.Cause(p.ctx)
+		}
+	}
+}
+
+// Surplus returns the number of matches that were not queued because the counts
+// of their patterns were already reached.
+func (p *searchPool) Surplus() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.surplus
+}
+
+// Stop signals the goroutines to stop and waits for them to return.
+// It returns the matches that were found but not returned by Next.
+func (p *searchPool) Stop() []searchMatch {
+	p.cancel(context.Canceled)
+	p.wg.Wait()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pending := p.matches
+	p.matches = nil
+	return pending
+}
+
+// findMatchingWalletConcurrent finds a matching wallet concurrently using a searchPool
+// of the specified number of goroutines, and returns the first matching wallet.
+// It returns the context's error if ctx is done before a match is found.
+func findMatchingWalletConcurrent(ctx context.Context, m matcher, goroutines int, attempts *atomic.Uint64) (wallet, error) {
+	p := startSearchPool(ctx, m, 1, goroutines, attempts, searchBudget{})
+	defer p.Stop()
+
+	match, err := p.Next(ctx)
+	return match.Wallet, err
 }
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatcher_Closeness(t *testing.T) {
+	cosmos := AvailableChains[1]
+
+	assert.Equal(t, 3, matcher{Mode: "starts-with", SearchString: "acdef", Chain: cosmos}.Closeness("cosmos1acdxx"))
+	assert.Equal(t, 0, matcher{Mode: "starts-with", SearchString: "acdef", Chain: cosmos}.Closeness("cosmos1xacde"))
+	assert.Equal(t, 2, matcher{Mode: "ends-with", SearchString: "acdef", Chain: cosmos}.Closeness("cosmos1xxxef"))
+	assert.Equal(t, 4, matcher{Mode: "contains", SearchString: "acdef", Chain: cosmos}.Closeness("cosmos1xacdex"))
+	assert.Equal(t, 0, matcher{Mode: "regex", SearchString: "^acdef", Chain: cosmos}.Closeness("cosmos1acdef"))
+}
+
+func TestMatcher_CaseSensitive(t *testing.T) {
+	berachain := AvailableChains[3]
+	address := "0xAb1f49947570eC8e7351b8EBf49e349e92fE0690"
+
+	// Case-insensitive matching ignores the checksum case of the address
+	assert.True(t, matcher{Mode: "starts-with", SearchString: "ab1f", Chain: berachain}.Match(address))
+	assert.True(t, matcher{Mode: "contains", SearchString: "ebf4", Chain: berachain}.Match(address))
+	assert.Equal(t, 3, matcher{Mode: "starts-with", SearchString: "ab1e", Chain: berachain}.Closeness(address))
+
+	checksum := matcher{Mode: "starts-with", SearchString: "Ab1f", Chain: berachain, CaseSensitive: true}
+	assert.Empty(t, checksum.ValidateInput())
+	assert.True(t, checksum.Match(address))
+	assert.Equal(t, 3, checksum.casedLetters())
+
+	checksum.SearchString = "ab1f"
+	assert.False(t, checksum.Match(address))
+	assert.Equal(t, 0, checksum.Closeness(address))
+
+	errs := matcher{Mode: "starts-with", SearchString: "ac", Chain: AvailableChains[1], CaseSensitive: true}.ValidateInput()
+	assert.Equal(t, []string{"ERROR: Checksum case matching is only supported for EVM chains with 0x addresses."}, errs)
+
+	// Uppercase regex literals can't match lowercased addresses
+	assert.NotEmpty(t, matcher{Mode: "regex", SearchString: "^AB", Chain: berachain}.ValidateInput())
+	assert.Empty(t, matcher{Mode: "regex", SearchString: "^AB", Chain: berachain, CaseSensitive: true}.ValidateInput())
+}
+
+func TestFindMatchingWalletConcurrent(t *testing.T) {
+	m := matcher{Mode: "contains", SearchString: "a", Chain: AvailableChains[0]}
+	var attempts atomic.Uint64
+	w, err := findMatchingWalletConcurrent(context.Background(), m, 2, &attempts)
+	assert.NoError(t, err)
+	assert.True(t, m.Match(w.Address))
+	assert.NotZero(t, attempts.Load())
+}
+
+func TestSearchPool(t *testing.T) {
+	m := matcher{Mode: "contains", SearchString: "a", Chain: AvailableChains[0]}
+	var attempts atomic.Uint64
+	pool := startSearchPool(context.Background(), m, 5, 3, &attempts, searchBudget{})
+
+	seen := make(map[string]bool)
+	for i := 0; i < 5; i++ {
+		match, err := pool.Next(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, m.Match(match.Wallet.Address))
+		assert.False(t, seen[match.Wallet.Address])
+		seen[match.Wallet.Address] = true
+	}
+	pool.Stop()
+
+	// No goroutine generates wallets after Stop returns
+	stopped := attempts.Load()
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, stopped, attempts.Load())
+}
+
+func TestSearchPool_KeepsEveryMatch(t *testing.T) {
+	p := newSearchPool(context.Background(), matcher{}, 0, new(atomic.Uint64), searchBudget{})
+
+	// Matches found while nobody waits in Next are queued in order, duplicates are dropped
+	p.add(wallet{Address: "cosmos1a"}, []int{0})
+	p.add(wallet{Address: "cosmos1b"}, []int{0})
+	p.add(wallet{Address: "cosmos1a"}, []int{0})
+	p.add(wallet{Address: "cosmos1c"}, []int{0})
+
+	for _, address := range []string{"cosmos1a", "cosmos1b"} {
+		match, err := p.Next(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, address, match.Wallet.Address)
+	}
+	assert.Equal(t, []searchMatch{{Wallet: wallet{Address: "cosmos1c"}}}, p.Stop())
+}
+
+func TestSearchPool_Count(t *testing.T) {
+	p := newSearchPool(context.Background(), matcher{Mode: "contains", SearchString: "a"}, 2, new(atomic.Uint64), searchBudget{})
+
+	// Matches beyond the requested accounts are only counted
+	p.add(wallet{Address: "cosmos1a"}, []int{0})
+	p.add(wallet{Address: "cosmos1b"}, []int{0})
+	p.add(wallet{Address: "cosmos1c"}, []int{0})
+	p.add(wallet{Address: "cosmos1d"}, []int{0})
+
+	assert.Len(t, p.Stop(), 2)
+	assert.Equal(t, 2, p.Surplus())
+}
+
+func TestSearchPool_PatternCounts(t *testing.T) {
+	m := matcher{Patterns: []pattern{
+		{Mode: "starts-with", Search: "a", Count: 1},
+		{Mode: "contains", Search: "q", Count: 2},
+	}}
+	p := newSearchPool(context.Background(), m, 0, new(atomic.Uint64), searchBudget{})
+
+	// A match is queued for the first of its patterns that still needs accounts
+	p.add(wallet{Address: "cosmos1aq"}, []int{0, 1})
+	p.add(wallet{Address: "cosmos1aqq"}, []int{0, 1})
+	p.add(wallet{Address: "cosmos1a"}, []int{0})
+	p.add(wallet{Address: "cosmos1q"}, []int{1})
+	p.add(wallet{Address: "cosmos1qq"}, []int{1})
+
+	assert.Equal(t, []searchMatch{
+		{Wallet: wallet{Address: "cosmos1aq"}, Pattern: m.Patterns[0]},
+		{Wallet: wallet{Address: "cosmos1aqq"}, Pattern: m.Patterns[1]},
+		{Wallet: wallet{Address: "cosmos1q"}, Pattern: m.Patterns[1]},
+	}, p.Stop())
+	assert.Equal(t, 2, p.Surplus())
+}
+
+func TestSearchPool_Cancel(t *testing.T) {
+	// Nothing matches the uppercase search string, so only cancellation ends the search
+	m := matcher{Mode: "contains", SearchString: "A", Chain: AvailableChains[0]}
+	var attempts atomic.Uint64
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := findMatchingWalletConcurrent(ctx, m, 2, &attempts)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NotZero(t, attempts.Load())
+}
+
+func TestSearchPool_Budget(t *testing.T) {
+	m := matcher{Mode: "contains", SearchString: "qqqqqqqq", Chain: AvailableChains[0]}
+
+	var attempts atomic.Uint64
+	pool := startSearchPool(context.Background(), m, 1, 2, &attempts, searchBudget{MaxAttempts: 50})
+	_, err := pool.Next(context.Background())
+	assert.ErrorIs(t, err, errMaxAttempts)
+	pool.Stop()
+	assert.Equal(t, uint64(50), attempts.Load())
+
+	best, ok := pool.BestMiss()
+	assert.True(t, ok)
+	assert.Equal(t, best.Closeness, m.Closeness(best.Wallet.Address))
+
+	pool = startSearchPool(context.Background(), m, 1, 2, new(atomic.Uint64), searchBudget{MaxDuration: 20 * time.Millisecond})
+	_, err = pool.Next(context.Background())
+	assert.ErrorIs(t, err, errMaxDuration)
+	pool.Stop()
+}
+
+func TestSearchPool_MayBeCloser(t *testing.T) {
+	m := matcher{Patterns: []pattern{{Mode: "starts-with", Search: "acd"}, {Mode: "contains", Search: "xyz"}}, Chain: AvailableChains[1]}
+	pool := newSearchPool(context.Background(), m, 0, new(atomic.Uint64), searchBudget{})
+
+	assert.True(t, pool.mayBeCloser(wallet{Address: "cosmos1acqqq"}, 1))
+	assert.False(t, pool.mayBeCloser(wallet{Address: "cosmos1acqqq"}, 2))
+	assert.True(t, pool.mayBeCloser(wallet{Address: "cosmos1qqxyq"}, 1))
+	assert.False(t, pool.mayBeCloser(wallet{Address: "cosmos1qqxyq"}, 2))
+
+	zeros := matcher{Mode: "leading-zero-bytes", SearchString: "4", Chain: AvailableChains[3]}
+	pool = newSearchPool(context.Background(), zeros, 0, new(atomic.Uint64), searchBudget{})
+	assert.True(t, pool.mayBeCloser(wallet{AddressBytes: []byte{0, 0, 1}}, 1))
+	assert.False(t, pool.mayBeCloser(wallet{AddressBytes: []byte{0, 0, 1}}, 2))
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/crypto/hd"
+	"github.com/cosmos/go-bip39"
+)
+
+// mnemonicEntropyBits maps the supported BIP39 mnemonic lengths to their entropy size in bits.
+var mnemonicEntropyBits = map[int]int{
+	12: 128,
+	24: 256,
+}
+
+// mnemonicGenerator wraps a Generator so that every candidate wallet is derived from a fresh
+// BIP39 mnemonic along a BIP44 path, instead of from a bare random private key.
+type mnemonicGenerator struct {
+	Generator
+	Words int    // number of words of the generated mnemonics
+	Path  string // BIP44 derivation path
+}
+
+// defaultHDPath returns the BIP44 path of the first address of the first account for a coin type.
+func defaultHDPath(coinType uint32) string {
+	return hd.CreateHDPath(coinType, 0, 0).String()
+}
+
+// newMnemonicGenerator returns a mnemonicGenerator for g.
+// It returns an error if the number of words or the derivation path is invalid.
+func newMnemonicGenerator(g Generator, words int, path string) (mnemonicGenerator, error) {
+	if _, ok := mnemonicEntropyBits[words]; !ok {
+		return mnemonicGenerator{}, fmt.Errorf("invalid mnemonic length %d, must be 12 or 24 words", words)
+	}
+	if _, err := hd.NewParamsFromPath(path); err != nil {
+		return mnemonicGenerator{}, fmt.Errorf("invalid HD path: %w", err)
+	}
+
+	return mnemonicGenerator{Generator: g, Words: words, Path: path}, nil
+}
+
+// GenerateWallet generates a new mnemonic and derives the wallet at the generator's path.
+func (g mnemonicGenerator) GenerateWallet() wallet {
+	entropy, err := bip39.NewEntropy(mnemonicEntropyBits[g.Words])
+	if err != nil {
+		panic(err)
+	}
+
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		panic(err)
+	}
+
+	generated, err := walletFromMnemonic(g.Generator, mnemonic, g.Path)
+	if err != nil {
+		panic(err)
+	}
+
+	return generated
+}
+
+// walletFromMnemonic derives the wallet of a mnemonic along a BIP44 path using the generator g.
+// The mnemonic and path are included in the returned wallet.
+func walletFromMnemonic(g Generator, mnemonic string, path string) (wallet, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, "")
+	if err != nil {
+		return wallet{}, err
+	}
+
+	master, chainCode := hd.ComputeMastersFromSeed(seed)
+	privateKey, err := hd.DerivePrivateKeyForPath(master, chainCode, path)
+	if err != nil {
+		return wallet{}, err
+	}
+
+	derived, err := g.WalletFromPrivateKey(privateKey)
+	if err != nil {
+		return wallet{}, err
+	}
+
+	derived.Mnemonic = mnemonic
+	derived.HDPath = path
+
+	return derived, nil
+}
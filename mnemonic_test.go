@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cosmos/go-bip39"
+	"github.com/stretchr/testify/assert"
+)
+
+// testMnemonic is the well known Hardhat/Foundry development mnemonic.
+const testMnemonic = "test test test test test test test test test test test junk"
+
+func TestWalletFromMnemonic(t *testing.T) {
+	w, err := walletFromMnemonic(ecsdaWallet{}, testMnemonic, "m/44'/60'/0'/0/0")
+	assert.NoError(t, err)
+	assert.Equal(t, "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266", w.Address)
+	assert.Equal(t, testMnemonic, w.Mnemonic)
+	assert.Equal(t, "m/44'/60'/0'/0/0", w.HDPath)
+
+	w, err = walletFromMnemonic(ecsdaWallet{}, testMnemonic, "m/44'/60'/0'/0/1")
+	assert.NoError(t, err)
+	assert.Equal(t, "0x70997970C51812dc3A010C7d01b50e0d17dc79C8", w.Address)
+
+	_, err = walletFromMnemonic(ecsdaWallet{}, "test test test", "m/44'/60'/0'/0/0")
+	assert.Error(t, err)
+}
+
+func TestNewMnemonicGenerator(t *testing.T) {
+	g := secp256k1Wallet{Chain: AvailableChains[1]}
+
+	_, err := newMnemonicGenerator(g, 15, defaultHDPath(118))
+	assert.ErrorContains(t, err, "must be 12 or 24 words")
+
+	_, err = newMnemonicGenerator(g, 12, "m/44'/118'")
+	assert.ErrorContains(t, err, "invalid HD path")
+
+	mg, err := newMnemonicGenerator(g, 12, defaultHDPath(118))
+	assert.NoError(t, err)
+
+	w := mg.GenerateWallet()
+	assert.True(t, strings.HasPrefix(w.Address, "cosmos1"))
+	assert.Len(t, strings.Fields(w.Mnemonic), 12)
+	assert.True(t, bip39.IsMnemonicValid(w.Mnemonic))
+	assert.Equal(t, "m/44'/118'/0'/0/0", w.HDPath)
+
+	// The printed mnemonic and path recover the same wallet
+	recovered, err := walletFromMnemonic(g, w.Mnemonic, w.HDPath)
+	assert.NoError(t, err)
+	assert.Equal(t, w, recovered)
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"golang.org/x/exp/slices"
+)
+
+// OutputFormats are the supported formats for found wallets.
+var OutputFormats = []string{"text", "json", "ndjson", "csv"}
+
+// result is a found wallet together with the metadata of the search that found it.
+type result struct {
+	Wallet   wallet
+	Index    int           // 1 based number of the result
+	Total    int           // number of requested accounts
+	Chain    chain         // chain the wallet was generated for
+	Mode     string        // matcher mode
+	Pattern  string        // search string
+	Score    float64       // score of the wallet in zero modes and scoring runs, 0 otherwise
+	Ranked   bool          // Index is the rank of the wallet in a scoring run
+	Word     wordMatch     // word found in the wallet in the words mode
+	Attempts uint64        // wallets generated before the result was found, counted from the start of the search
+	Elapsed  time.Duration // time since the start of the search
+}
+
+// resultRecord is the serialized form of a result in the json, ndjson and csv formats.
+type resultRecord struct {
+	Index          int     `json:"index"`
+	Chain          string  `json:"chain"`
+	Family         string  `json:"family"`
+	Mode           string  `json:"mode"`
+	Pattern        string  `json:"pattern"`
+	Score          float64 `json:"score,omitempty"`
+	Word           string  `json:"word,omitempty"`
+	WordForm       string  `json:"word_form,omitempty"`
+	WordOffset     *int    `json:"word_offset,omitempty"`
+	Address        string  `json:"address"`
+	HexAddress     string  `json:"hex_address,omitempty"`
+	PublicKey      string  `json:"public_key"`
+	PrivateKey     string  `json:"private_key,omitempty"`
+	PartialKey     bool    `json:"partial_key,omitempty"`
+	Mnemonic       string  `json:"mnemonic,omitempty"`
+	HDPath         string  `json:"hd_path,omitempty"`
+	Attempts       uint64  `json:"attempts"`
+	ElapsedSeconds float64 `json:"elapsed_seconds"`
+}
+
+// resultRecordHeader is the csv header row, in the order of resultRecord.csvRow.
+var resultRecordHeader = []string{
+	"index", "chain", "family", "mode", "pattern", "address", "hex_address", "public_key", "private_key",
+	"partial_key", "mnemonic", "hd_path", "attempts", "elapsed_seconds", "score",
+	"word", "word_form", "word_offset",
+}
+
+// record returns the serialized form of the result.
+func (r result) record() resultRecord {
+	// The word form is only written if leetspeak changed the word
+	var wordForm string
+	var wordOffset *int
+	if r.Word.Word != "" {
+		wordOffset = &r.Word.Offset
+		if r.Word.Form != r.Word.Word {
+			wordForm = r.Word.Form
+		}
+	}
+
+	return resultRecord{
+		Index:          r.Index,
+		Chain:          r.Chain.Name,
+		Family:         r.Chain.Encryption.String(),
+		Mode:           r.Mode,
+		Pattern:        r.Pattern,
+		Score:          r.Score,
+		Word:           r.Word.Word,
+		WordForm:       wordForm,
+		WordOffset:     wordOffset,
+		Address:        r.Wallet.Address,
+		HexAddress:     r.Wallet.HexAddress,
+		PublicKey:      hex.EncodeToString(r.Wallet.PublicKey),
+		PrivateKey:     hex.EncodeToString(r.Wallet.PrivateKey),
+		PartialKey:     r.Wallet.PartialKey,
+		Mnemonic:       r.Wallet.Mnemonic,
+		HDPath:         r.Wallet.HDPath,
+		Attempts:       r.Attempts,
+		ElapsedSeconds: r.Elapsed.Seconds(),
+	}
+}
+
+// csvRow returns the record as a csv row.
+func (rec resultRecord) csvRow() []string {
+	var wordOffset string
+	if rec.WordOffset != nil {
+		wordOffset = strconv.Itoa(*rec.WordOffset)
+	}
+
+	return []string{
+		strconv.Itoa(rec.Index), rec.Chain, rec.Family, rec.Mode, rec.Pattern, rec.Address, rec.HexAddress,
+		rec.PublicKey, rec.PrivateKey, strconv.FormatBool(rec.PartialKey), rec.Mnemonic, rec.HDPath,
+		strconv.FormatUint(rec.Attempts, 10), strconv.FormatFloat(rec.ElapsedSeconds, 'f', 3, 64),
+		strconv.FormatFloat(rec.Score, 'g', -1, 64), rec.Word, rec.WordForm, wordOffset,
+	}
+}
+
+// resultWriter streams results to an output as they are found.
+type resultWriter interface {
+	// Write writes a single result.
+	Write(r result) error
+
+	// Close finishes the output, it doesn't close the underlying writer.
+	Close() error
+}
+
+// newResultWriter returns a resultWriter for the given format writing to out.
+func newResultWriter(format string, out io.Writer) (resultWriter, error) {
+	switch format {
+	case "text":
+		return &textResultWriter{out: out}, nil
+	case "json":
+		return &jsonResultWriter{out: out}, nil
+	case "ndjson":
+		return &ndjsonResultWriter{encoder: json.NewEncoder(out)}, nil
+	case "csv":
+		return &csvResultWriter{csv: csv.NewWriter(out)}, nil
+	default:
+		return nil, fmt.Errorf("invalid output format %q, must be one of: %v", format, OutputFormats)
+	}
+}
+
+// textResultWriter writes results in the human readable format of wallet.String.
+type textResultWriter struct {
+	out io.Writer
+}
+
+func (w *textResultWriter) Write(r result) error {
+	s := r.Wallet.String()
+	if slices.Contains(ZeroModes, r.Mode) {
+		s += fmt.Sprintf("\nScore:\t\t%g %s", r.Score, zeroModeUnit(r.Mode))
+	} else if slices.Contains(StructureModes, r.Mode) {
+		s += fmt.Sprintf("\nScore:\t\t%g %s", r.Score, structureModeUnit(r.Mode))
+	} else if r.Word.Word != "" {
+		s += "\nWord:\t\t" + r.Word.String()
+	} else if r.Ranked {
+		s += fmt.Sprintf("\nScore:\t\t%g (%s)", r.Score, r.Pattern)
+	}
+
+	header := "Found a new matching wallet"
+	if r.Ranked {
+		header = "Rank"
+	}
+
+	_, err := fmt.Fprintf(w.out, "\n%s (%d out of %d):\n%s\n", header, r.Index, r.Total, s)
+	return err
+}
+
+func (w *textResultWriter) Close() error {
+	return nil
+}
+
+// jsonResultWriter writes results as a JSON array, streaming the elements as they are found.
+type jsonResultWriter struct {
+	out     io.Writer
+	written int
+}
+
+func (w *jsonResultWriter) Write(r result) error {
+	data, err := json.MarshalIndent(r.record(), "  ", "  ")
+	if err != nil {
+		return err
+	}
+
+	separator := ",\n  "
+	if w.written == 0 {
+		separator = "[\n  "
+	}
+	w.written++
+
+	_, err = io.WriteString(w.out, separator+string(data))
+	return err
+}
+
+func (w *jsonResultWriter) Close() error {
+	end := "\n]\n"
+	if w.written == 0 {
+		end = "[]\n"
+	}
+
+	_, err := io.WriteString(w.out, end)
+	return err
+}
+
+// ndjsonResultWriter writes one JSON object per line per result.
+type ndjsonResultWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *ndjsonResultWriter) Write(r result) error {
+	return w.encoder.Encode(r.record())
+}
+
+func (w *ndjsonResultWriter) Close() error {
+	return nil
+}
+
+// csvResultWriter writes results as csv rows after a header row.
+type csvResultWriter struct {
+	csv           *csv.Writer
+	headerWritten bool
+}
+
+func (w *csvResultWriter) Write(r result) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	if err := w.csv.Write(r.record().csvRow()); err != nil {
+		return err
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+func (w *csvResultWriter) Close() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+// writeHeader writes the header row once.
+func (w *csvResultWriter) writeHeader() error {
+	if w.headerWritten {
+		return nil
+	}
+	w.headerWritten = true
+	return w.csv.Write(resultRecordHeader)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testResult(index int) result {
+	return result{
+		Wallet:   wallet{Address: "cosmos1acde", PublicKey: []byte{1, 2}, PrivateKey: []byte{3, 4}},
+		Index:    index,
+		Total:    2,
+		Chain:    AvailableChains[1],
+		Mode:     "starts-with",
+		Pattern:  "acde",
+		Attempts: 1000,
+		Elapsed:  1500 * time.Millisecond,
+	}
+}
+
+func writeResults(t *testing.T, format string, results ...result) string {
+	var buf bytes.Buffer
+	w, err := newResultWriter(format, &buf)
+	assert.NoError(t, err)
+	for _, r := range results {
+		assert.NoError(t, w.Write(r))
+	}
+	assert.NoError(t, w.Close())
+	return buf.String()
+}
+
+func TestResultWriter_JSON(t *testing.T) {
+	var records []resultRecord
+	assert.NoError(t, json.Unmarshal([]byte(writeResults(t, "json", testResult(1), testResult(2))), &records))
+	assert.Equal(t, []resultRecord{testResult(1).record(), testResult(2).record()}, records)
+	assert.Equal(t, resultRecord{
+		Index:          1,
+		Chain:          "cosmos",
+		Family:         "Secp256k1",
+		Mode:           "starts-with",
+		Pattern:        "acde",
+		Address:        "cosmos1acde",
+		PublicKey:      "0102",
+		PrivateKey:     "0304",
+		Attempts:       1000,
+		ElapsedSeconds: 1.5,
+	}, records[0])
+
+	assert.Equal(t, "[]\n", writeResults(t, "json"))
+}
+
+func TestResultWriter_NDJSON(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(writeResults(t, "ndjson", testResult(1), testResult(2))), "\n")
+	assert.Len(t, lines, 2)
+
+	var record resultRecord
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+	assert.Equal(t, testResult(2).record(), record)
+}
+
+func TestResultWriter_CSV(t *testing.T) {
+	rows, err := csv.NewReader(strings.NewReader(writeResults(t, "csv", testResult(1)))).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		resultRecordHeader,
+		{"1", "cosmos", "Secp256k1", "starts-with", "acde", "cosmos1acde", "", "0102", "0304", "false", "", "", "1000", "1.500", "0", "", "", ""},
+	}, rows)
+
+	assert.Equal(t, strings.Join(resultRecordHeader, ",")+"\n", writeResults(t, "csv"))
+}
+
+func TestResultWriter_Text(t *testing.T) {
+	out := writeResults(t, "text", testResult(1))
+	assert.Contains(t, out, "Found a new matching wallet (1 out of 2):")
+	assert.Contains(t, out, "Address:\tcosmos1acde")
+}
+
+func TestNewResultWriter_Invalid(t *testing.T) {
+	_, err := newResultWriter("yaml", &bytes.Buffer{})
+	assert.ErrorContains(t, err, "invalid output format")
+}
+
+func TestResultWriter_WithoutPrivateKey(t *testing.T) {
+	r := testResult(1)
+	r.Wallet.PrivateKey = nil
+
+	out := writeResults(t, "text", r)
+	assert.NotContains(t, out, "Private key:")
+	assert.Contains(t, out, "Public key:\t0102")
+
+	assert.NotContains(t, writeResults(t, "json", r), "private_key")
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// pattern is one search of a patterns file.
+type pattern struct {
+	Mode   string // matcher mode
+	Search string // search string
+	Count  int    // number of accounts to find, unlimited if 0
+}
+
+// readPatternsFile reads a patterns file. See parsePatterns for the format.
+func readPatternsFile(path string, defaultCount int, caseSensitive bool) ([]pattern, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return parsePatterns(file, defaultCount, caseSensitive)
+}
+
+// parsePatterns parses patterns, one per line in the form "<mode> <search string> [count]".
+// The count defaults to defaultCount. Empty lines and lines starting with # are skipped.
+// Search strings other than regexes are lowercased like the --search flag, unless caseSensitive is set.
+func parsePatterns(r io.Reader, defaultCount int, caseSensitive bool) ([]pattern, error) {
+	var patterns []pattern
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("line %d: expected <mode> <search string> [count]", line)
+		}
+
+		p := pattern{Mode: fields[0], Search: fields[1], Count: defaultCount}
+		if !slices.Contains(MatcherModes, p.Mode) {
+			return nil, fmt.Errorf("line %d: invalid matcher mode %q, must be one of: %v", line, p.Mode, MatcherModes)
+		}
+		if p.Mode != "regex" && !caseSensitive {
+			p.Search = strings.ToLower(p.Search)
+		}
+
+		if len(fields) == 3 {
+			count, err := strconv.Atoi(fields[2])
+			if err != nil || count < 1 {
+				return nil, fmt.Errorf("line %d: invalid count %q, must be a positive number", line, fields[2])
+			}
+			p.Count = count
+		}
+
+		patterns = append(patterns, p)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("no patterns found")
+	}
+
+	return patterns, nil
+}
+
+// patternSet is a compiled set of patterns, evaluated against a candidate in a single pass.
+// All contains patterns are found by one Aho-Corasick automaton, the other patterns are
+// compiled into predicates.
+type patternSet struct {
+	contains         *ahoCorasick
+	containsPattern  []int // pattern index of each word of the automaton
+	predicates       []predicate
+	predicatePattern []int // pattern index of each predicate
+}
+
+// compilePatterns compiles patterns into a patternSet.
+// It returns an error if a regex pattern is invalid.
+func compilePatterns(patterns []pattern) (*patternSet, error) {
+	set := &patternSet{}
+	var words []string
+
+	for i, p := range patterns {
+		if p.Mode == "contains" {
+			words = append(words, p.Search)
+			set.containsPattern = append(set.containsPattern, i)
+			continue
+		}
+
+		match, err := matcher{Mode: p.Mode, SearchString: p.Search}.compile()
+		if err != nil {
+			return nil, fmt.Errorf("pattern %q: %w", p.Search, err)
+		}
+		set.predicates = append(set.predicates, match)
+		set.predicatePattern = append(set.predicatePattern, i)
+	}
+
+	if len(words) > 0 {
+		set.contains = newAhoCorasick(words)
+	}
+
+	return set, nil
+}
+
+// Match returns the indices of the patterns the candidate matches in ascending order,
+// or nil if it matches none.
+func (s *patternSet) Match(candidate string) []int {
+	var matches []int
+
+	if s.contains != nil {
+		s.contains.Find(candidate, func(word int, end int) {
+			if i := s.containsPattern[word]; !slices.Contains(matches, i) {
+				matches = append(matches, i)
+			}
+		})
+	}
+
+	for j, match := range s.predicates {
+		if match(candidate) {
+			matches = append(matches, s.predicatePattern[j])
+		}
+	}
+
+	slices.Sort(matches)
+	return matches
+}
+
+// ahoCorasick is an Aho-Corasick automaton, finding all occurrences of several words
+// in a text in one pass over the text.
+type ahoCorasick struct {
+	next [][256]int32 // transitions of every state, including the failure transitions
+	out  [][]int      // indices of the words ending in every state
+}
+
+// newAhoCorasick builds the automaton for the words.
+func newAhoCorasick(words []string) *ahoCorasick {
+	a := &ahoCorasick{next: make([][256]int32, 1), out: make([][]int, 1)}
+
+	// Build the trie of the words, 0 is the root and no state transitions back to it yet
+	for i, word := range words {
+		state := int32(0)
+		for j := 0; j < len(word); j++ {
+			if a.next[state][word[j]] == 0 {
+				a.next = append(a.next, [256]int32{})
+				a.out = append(a.out, nil)
+				a.next[state][word[j]] = int32(len(a.next) - 1)
+			}
+			state = a.next[state][word[j]]
+		}
+		a.out[state] = append(a.out[state], i)
+	}
+
+	// Complete the transitions with the failure links in breadth first order, so the
+	// transitions of the failure state of every state are complete when it is visited
+	fail := make([]int32, len(a.next))
+	queue := make([]int32, 0, len(a.next))
+	for c := 0; c < 256; c++ {
+		if child := a.next[0][c]; child != 0 {
+			queue = append(queue, child)
+		}
+	}
+
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		a.out[state] = append(a.out[state], a.out[fail[state]]...)
+
+		for c := 0; c < 256; c++ {
+			child := a.next[state][c]
+			if child == 0 {
+				a.next[state][c] = a.next[fail[state]][c]
+				continue
+			}
+			fail[child] = a.next[fail[state]][c]
+			queue = append(queue, child)
+		}
+	}
+
+	return a
+}
+
+// Find calls found with the index of every word occurring in text and the index in text right
+// after the occurrence, once per occurrence.
+func (a *ahoCorasick) Find(text string, found func(word int, end int)) {
+	state := int32(0)
+	for i := 0; i < len(text); i++ {
+		state = a.next[state][text[i]]
+		for _, word := range a.out[state] {
+			found(word, i+1)
+		}
+	}
+}
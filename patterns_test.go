@@ -0,0 +1,89 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePatterns(t *testing.T) {
+	patterns, err := parsePatterns(strings.NewReader(`
+# team names
+starts-with  Team  2
+contains     forge
+regex        ^a\d+$ 1
+`), 3, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []pattern{
+		{Mode: "starts-with", Search: "team", Count: 2},
+		{Mode: "contains", Search: "forge", Count: 3},
+		{Mode: "regex", Search: `^a\d+$`, Count: 1},
+	}, patterns)
+
+	patterns, err = parsePatterns(strings.NewReader("starts-with DeAd"), 1, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []pattern{{Mode: "starts-with", Search: "DeAd", Count: 1}}, patterns)
+
+	_, err = parsePatterns(strings.NewReader("prefix team"), 1, false)
+	assert.ErrorContains(t, err, `line 1: invalid matcher mode "prefix"`)
+
+	_, err = parsePatterns(strings.NewReader("\ncontains team 0"), 1, false)
+	assert.ErrorContains(t, err, `line 2: invalid count "0"`)
+
+	_, err = parsePatterns(strings.NewReader("contains"), 1, false)
+	assert.ErrorContains(t, err, "expected <mode> <search string> [count]")
+
+	_, err = parsePatterns(strings.NewReader("# nothing"), 1, false)
+	assert.ErrorContains(t, err, "no patterns found")
+}
+
+func TestAhoCorasick(t *testing.T) {
+	a := newAhoCorasick([]string{"he", "she", "his", "hers"})
+
+	var found []int
+	a.Find("ushers", func(word int, end int) { found = append(found, word) })
+	assert.ElementsMatch(t, []int{1, 0, 3}, found)
+
+	found = nil
+	a.Find("xyz", func(word int, end int) { found = append(found, word) })
+	assert.Empty(t, found)
+}
+
+func TestPatternSet_Match(t *testing.T) {
+	set, err := compilePatterns([]pattern{
+		{Mode: "contains", Search: "cde"},
+		{Mode: "starts-with", Search: "ac"},
+		{Mode: "contains", Search: "de"},
+		{Mode: "regex", Search: "q$"},
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []int{0, 1, 2}, set.Match("acdexx"))
+	assert.Equal(t, []int{2, 3}, set.Match("xdexq"))
+	assert.Nil(t, set.Match("xxxx"))
+
+	_, err = compilePatterns([]pattern{{Mode: "regex", Search: "(a"}})
+	assert.Error(t, err)
+}
+
+func TestMatcher_Patterns(t *testing.T) {
+	m := matcher{Chain: AvailableChains[1], Patterns: []pattern{
+		{Mode: "starts-with", Search: "acde", Count: 1},
+		{Mode: "ends-with", Search: "qq", Count: 1},
+	}}
+
+	assert.Empty(t, m.ValidateInput())
+	assert.True(t, m.Match("cosmos1acdexx"))
+	assert.Equal(t, []int{0, 1}, m.MatchPatterns("cosmos1acdeqq"))
+	assert.Nil(t, m.MatchPatterns("cosmos1xxxx"))
+
+	closeness, closest := m.closest("cosmos1acdxxq", m.patterns())
+	assert.Equal(t, 3, closeness)
+	assert.Equal(t, m.Patterns[0], closest)
+
+	m.Patterns = append(m.Patterns, pattern{Mode: "contains", Search: "bio", Count: 1})
+	errs := m.ValidateInput()
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0], "ERROR: Pattern contains bio: bio contains bech32 incompatible characters.")
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// predicate reports whether an address, without the chain prefix, matches a search.
+type predicate func(candidate string) bool
+
+// compile compiles the mode and search string of the matcher, or its conditions, into a predicate.
+// It returns an error if the search string is an invalid regex.
+func (m matcher) compile() (predicate, error) {
+	if len(m.Conditions) > 0 {
+		return compileConditions(m.Conditions), nil
+	}
+
+	search := m.SearchString
+
+	switch m.Mode {
+	case "starts-with":
+		return func(candidate string) bool { return strings.HasPrefix(candidate, search) }, nil
+	case "ends-with":
+		return func(candidate string) bool { return strings.HasSuffix(candidate, search) }, nil
+	case "regex":
+		return compileRegex(search)
+	case wordsMode:
+		words, err := m.words()
+		if err != nil {
+			return nil, err
+		}
+		return func(candidate string) bool {
+			_, ok := words.Find(candidate)
+			return ok
+		}, nil
+	case "leading-zero-bytes", "zero-nibbles":
+		minScore, err := strconv.Atoi(search)
+		if err != nil {
+			return nil, err
+		}
+		return compileZeros(m.Mode, minScore), nil
+	case "runs", "repeats", "palindromes", "sequences":
+		minScore, err := strconv.Atoi(search)
+		if err != nil {
+			return nil, err
+		}
+		return compileStructure(m.Mode, minScore), nil
+	default:
+		return func(candidate string) bool { return strings.Contains(candidate, search) }, nil
+	}
+}
+
+// regexLiterals is literal text that every match of a regex starts with, ends with or contains.
+type regexLiterals struct {
+	Prefix   string
+	Suffix   string
+	Contains []string
+}
+
+// compileRegex compiles a regex into a predicate. The literal text every match needs is
+// checked with string functions first, which rejects most candidates without running the regex.
+func compileRegex(expr string) (predicate, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	literals := findRegexLiterals(parsed.Simplify())
+
+	return func(candidate string) bool {
+		if !strings.HasPrefix(candidate, literals.Prefix) || !strings.HasSuffix(candidate, literals.Suffix) {
+			return false
+		}
+		for _, literal := range literals.Contains {
+			if !strings.Contains(candidate, literal) {
+				return false
+			}
+		}
+		return re.MatchString(candidate)
+	}, nil
+}
+
+// findRegexLiterals finds the case sensitive literals in the top level concatenation of a regex.
+// A literal right after ^ is a prefix, a literal right before $ is a suffix, and every other one
+// has to be contained in a match.
+func findRegexLiterals(re *syntax.Regexp) regexLiterals {
+	parts := []*syntax.Regexp{re}
+	if re.Op == syntax.OpConcat {
+		parts = re.Sub
+	}
+
+	var literals regexLiterals
+	for i, part := range parts {
+		for part.Op == syntax.OpCapture {
+			part = part.Sub[0]
+		}
+		if part.Op != syntax.OpLiteral || part.Flags&syntax.FoldCase != 0 {
+			continue
+		}
+
+		literal := string(part.Rune)
+		switch {
+		case i > 0 && parts[i-1].Op == syntax.OpBeginText:
+			literals.Prefix = literal
+		case i < len(parts)-1 && parts[i+1].Op == syntax.OpEndText:
+			literals.Suffix = literal
+		default:
+			literals.Contains = append(literals.Contains, literal)
+		}
+	}
+
+	return literals
+}
+
+// validateRegex checks that expr is a valid regex whose literals and character classes
+// can match characters of the alphabet. It returns a slice of validation error messages.
+func validateRegex(expr string, alphabet string) []string {
+	parsed, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return []string{"ERROR: Invalid regex: " + err.Error() + "."}
+	}
+
+	var errs []string
+	walkRegex(parsed, func(re *syntax.Regexp) {
+		switch re.Op {
+		case syntax.OpLiteral:
+			for _, r := range re.Rune {
+				if !strings.ContainsRune(alphabet, r) &&
+					(re.Flags&syntax.FoldCase == 0 || !strings.ContainsAny(alphabet, string(unicode.SimpleFold(r))+string(r))) {
+					errs = append(errs, fmt.Sprintf("ERROR: Regex literal %q contains characters that can't appear in addresses.", string(re.Rune)))
+					return
+				}
+			}
+		case syntax.OpCharClass:
+			if !strings.ContainsFunc(alphabet, func(r rune) bool { return inCharClass(re.Rune, r) }) {
+				errs = append(errs, "ERROR: Regex character class "+re.String()+" matches no address characters.")
+			}
+		}
+	})
+
+	return errs
+}
+
+// walkRegex calls fn for re and all of its sub expressions.
+func walkRegex(re *syntax.Regexp, fn func(re *syntax.Regexp)) {
+	fn(re)
+	for _, sub := range re.Sub {
+		walkRegex(sub, fn)
+	}
+}
+
+// inCharClass reports whether r is in the ranges of a regex character class.
+func inCharClass(ranges []rune, r rune) bool {
+	for i := 0; i+1 < len(ranges); i += 2 {
+		if ranges[i] <= r && r <= ranges[i+1] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"regexp/syntax"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatcher_Compile(t *testing.T) {
+	tests := []struct {
+		mode, search, candidate string
+		match                   bool
+	}{
+		{"starts-with", "acde", "acdexx", true},
+		{"starts-with", "acde", "xacde", false},
+		{"ends-with", "acde", "xxacde", true},
+		{"contains", "acde", "xacdex", true},
+		{"contains", "acde", "xacdfx", false},
+		{"regex", "^ac[0-9]+$", "ac0123", true},
+		{"regex", "^ac[0-9]+$", "ac01x3", false},
+		{"regex", "q.*q", "xqxxqx", true},
+	}
+
+	for _, tt := range tests {
+		match, err := matcher{Mode: tt.mode, SearchString: tt.search}.compile()
+		assert.NoError(t, err)
+		assert.Equal(t, tt.match, match(tt.candidate), "%s %s %s", tt.mode, tt.search, tt.candidate)
+	}
+
+	_, err := matcher{Mode: "regex", SearchString: "(ac"}.compile()
+	assert.Error(t, err)
+}
+
+func TestFindRegexLiterals(t *testing.T) {
+	literals := func(expr string) regexLiterals {
+		re, err := syntaxParse(expr)
+		assert.NoError(t, err)
+		return findRegexLiterals(re)
+	}
+
+	assert.Equal(t, regexLiterals{Prefix: "acde"}, literals("^acde"))
+	assert.Equal(t, regexLiterals{Suffix: "acde"}, literals("[0-9]acde$"))
+	assert.Equal(t, regexLiterals{Prefix: "ac", Suffix: "zz", Contains: []string{"qq"}}, literals("^ac.*qq.*zz$"))
+	assert.Equal(t, regexLiterals{Contains: []string{"qq"}}, literals("(qq)[0-9]"))
+	assert.Equal(t, regexLiterals{}, literals("acde|qqqq"))
+	assert.Equal(t, regexLiterals{}, literals("(?i)acde"))
+}
+
+func TestMatcher_ValidateInput_Regex(t *testing.T) {
+	cosmos := AvailableChains[1]
+	berachain := AvailableChains[3]
+
+	assert.Empty(t, matcher{Mode: "regex", SearchString: `^ac\d{2,}[^0-9]$`, Chain: cosmos}.ValidateInput())
+	assert.Empty(t, matcher{Mode: "regex", SearchString: `^(dead|beef)`, Chain: berachain}.ValidateInput())
+
+	errs := matcher{Mode: "regex", SearchString: "^(ac", Chain: cosmos}.ValidateInput()
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0], "Invalid regex")
+
+	// b, i, o and 1 are not part of the bech32 alphabet
+	errs = matcher{Mode: "regex", SearchString: "^bio|ac", Chain: cosmos}.ValidateInput()
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0], `"bio" contains characters that can't appear`)
+
+	errs = matcher{Mode: "regex", SearchString: "^[g-z]", Chain: berachain}.ValidateInput()
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0], "matches no address characters")
+}
+
+func syntaxParse(expr string) (*syntax.Regexp, error) {
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	return re.Simplify(), nil
+}
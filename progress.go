@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
+)
+
+// progressInterval is the interval of status lines when the output is not a terminal.
+const progressInterval = 10 * time.Second
+
+// progress tracks a running search for status reporting. The attempt counter is shared
+// with the search workers, found matches are counted with Found.
+type progress struct {
+	attempts   *atomic.Uint64
+	found      atomic.Int64
+	total      int
+	difficulty difficulty
+	start      time.Time
+	ranked     func() string // status of a scoring run, shown instead of the found matches if set
+}
+
+// newProgress returns a progress for a search of total accounts counting attempts.
+// The difficulty is used for the ETA, which is left out if its probability is unknown.
+func newProgress(attempts *atomic.Uint64, total int, d difficulty) *progress {
+	return &progress{attempts: attempts, total: total, difficulty: d, start: time.Now()}
+}
+
+// Start restarts the elapsed time, it must be called before the search workers start.
+func (p *progress) Start() {
+	p.start = time.Now()
+}
+
+// Found counts a found match.
+func (p *progress) Found() {
+	p.found.Add(1)
+}
+
+// Elapsed returns the time since the start of the search.
+func (p *progress) Elapsed() time.Duration {
+	return time.Since(p.start)
+}
+
+// Rate returns the average number of attempts per second since the start of the search.
+func (p *progress) Rate() float64 {
+	elapsed := p.Elapsed().Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(p.attempts.Load()) / elapsed
+}
+
+// ETA returns the expected time in seconds to find the remaining matches at the current rate.
+// It returns false if the difficulty or the rate is unknown.
+func (p *progress) ETA() (float64, bool) {
+	rate := p.Rate()
+	if p.difficulty.Probability <= 0 || rate <= 0 {
+		return 0, false
+	}
+
+	remaining := p.total - int(p.found.Load())
+	return float64(remaining) * p.difficulty.ExpectedAttempts() / rate, true
+}
+
+// String returns a one line status of the search.
+func (p *progress) String() string {
+	found := fmt.Sprintf("%d/%d found", p.found.Load(), p.total)
+	if p.ranked != nil {
+		found = p.ranked()
+	}
+
+	status := []string{
+		found,
+		formatCount(float64(p.attempts.Load())) + " attempts",
+		formatCount(p.Rate()) + " keys/s",
+		"elapsed " + p.Elapsed().Round(time.Second).String(),
+	}
+
+	if eta, ok := p.ETA(); ok {
+		status = append(status, "ETA "+formatSeconds(eta))
+	}
+
+	return strings.Join(status, " | ")
+}
+
+// Summary returns a summary of the finished search, preceded by the reason it was stopped early, if any.
+func (p *progress) Summary(stopReason string) string {
+	summary := fmt.Sprintf("Found %d of %d wallets after %s attempts in %s (%s keys/s)",
+		p.found.Load(), p.total, formatCount(float64(p.attempts.Load())),
+		p.Elapsed().Round(time.Second), formatCount(p.Rate()))
+
+	if stopReason != "" {
+		summary = stopReason + " " + summary
+	}
+	return summary
+}
+
+// runWithProgress runs action while reporting the progress to out. On a terminal a spinner with
+// a live status is shown, otherwise a status line is written every progressInterval.
+// Pressing Ctrl+C on the spinner calls cancel, which must make action return.
+func runWithProgress(action func(), cancel func(), p *progress, out *os.File) {
+	if term.IsTerminal(int(out.Fd())) {
+		runProgressUI(action, cancel, p, out)
+	} else {
+		runProgressLines(action, p, out, progressInterval)
+	}
+}
+
+// runProgressLines runs action and writes the status to out every interval until it returns.
+func runProgressLines(action func(), p *progress, out io.Writer, interval time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		action()
+		close(done)
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			fmt.Fprintln(out, p)
+		}
+	}
+}
+
+// progressModel is the bubbletea model of the spinner with a live status.
+type progressModel struct {
+	spinner  spinner.Model
+	progress *progress
+	cancel   func()
+}
+
+var progressTitleStyle = lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#FFFDF5", Dark: "#FFFDF5"})
+
+func (m progressModel) Init() tea.Cmd {
+	return m.spinner.Tick
+}
+
+func (m progressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// The terminal is in raw mode, so Ctrl+C arrives as a key instead of SIGINT
+	if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "ctrl+c" {
+		m.cancel()
+		return m, tea.Quit
+	}
+
+	var cmd tea.Cmd
+	m.spinner, cmd = m.spinner.Update(msg)
+	return m, cmd
+}
+
+// View redraws the status on every spinner tick.
+func (m progressModel) View() string {
+	return m.spinner.View() + progressTitleStyle.Render(" Generating accounts... ") + m.progress.String()
+}
+
+// runProgressUI runs action while showing a spinner with a live status on out.
+// It returns once action has returned.
+func runProgressUI(action func(), cancel func(), p *progress, out io.Writer) {
+	s := spinner.New(spinner.WithSpinner(spinner.Meter))
+	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#F780E2"))
+
+	program := tea.NewProgram(progressModel{spinner: s, progress: p, cancel: cancel}, tea.WithOutput(out))
+
+	done := make(chan struct{})
+	go func() {
+		action()
+		close(done)
+		program.Quit()
+	}()
+
+	_, _ = program.Run()
+	<-done
+}
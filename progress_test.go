@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgress_String(t *testing.T) {
+	var attempts atomic.Uint64
+	p := newProgress(&attempts, 3, difficulty{Probability: 1.0 / 1000})
+	p.start = time.Now().Add(-10 * time.Second)
+	attempts.Store(20000)
+	p.Found()
+
+	assert.InDelta(t, 2000, p.Rate(), 10)
+	eta, ok := p.ETA()
+	assert.True(t, ok)
+	assert.InDelta(t, 1, eta, 0.01)
+
+	status := p.String()
+	assert.Contains(t, status, "1/3 found")
+	assert.Contains(t, status, "20.00k attempts")
+	assert.Contains(t, status, "elapsed 10s")
+	assert.Contains(t, status, "ETA 1.0 seconds")
+
+	// Without a difficulty estimate there is no ETA
+	p.difficulty = difficulty{}
+	_, ok = p.ETA()
+	assert.False(t, ok)
+	assert.NotContains(t, p.String(), "ETA")
+}
+
+func TestProgress_Summary(t *testing.T) {
+	var attempts atomic.Uint64
+	p := newProgress(&attempts, 5, difficulty{})
+	p.start = time.Now().Add(-4 * time.Second)
+	attempts.Store(400)
+	p.Found()
+
+	assert.Equal(t, "Found 1 of 5 wallets after 400 attempts in 4s (100 keys/s)", p.Summary(""))
+	assert.True(t, strings.HasPrefix(p.Summary("Search interrupted."), "Search interrupted. Found 1 of 5"))
+}
+
+func TestRunProgressLines(t *testing.T) {
+	var attempts atomic.Uint64
+	p := newProgress(&attempts, 1, difficulty{})
+
+	var out bytes.Buffer
+	runProgressLines(func() {
+		attempts.Add(100)
+		time.Sleep(50 * time.Millisecond)
+		p.Found()
+	}, p, &out, 10*time.Millisecond)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.GreaterOrEqual(t, len(lines), 2)
+	assert.Contains(t, lines[0], "0/1 found | 100 attempts")
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/pflag"
+)
+
+// registryChainFile is the name of the chain definition files in the Cosmos chain-registry.
+const registryChainFile = "chain.json"
+
+// registryChain holds the fields of a Cosmos chain-registry chain.json file that vanity-forge uses.
+type registryChain struct {
+	ChainName    string   `json:"chain_name"`
+	Bech32Prefix string   `json:"bech32_prefix"`
+	Slip44       *uint32  `json:"slip44"`
+	KeyAlgos     []string `json:"key_algos"`
+}
+
+// registryKeyAlgos maps chain-registry key algorithms to encryption names used in chains files.
+var registryKeyAlgos = map[string]string{
+	"secp256k1":    "secp256k1",
+	"ethsecp256k1": "ethsecp256k1",
+}
+
+// toChainConfig converts a chain-registry chain into a chain definition.
+// Chains without key_algos default to secp256k1 and chains without slip44 default to coin type 118.
+func (rc registryChain) toChainConfig() (chainConfig, error) {
+	if rc.ChainName == "" {
+		return chainConfig{}, errors.New("chain_name is missing")
+	}
+	if rc.Bech32Prefix == "" {
+		return chainConfig{}, errors.New("bech32_prefix is missing")
+	}
+
+	encryption := ""
+	if len(rc.KeyAlgos) == 0 {
+		encryption = "secp256k1"
+	}
+	for _, algo := range rc.KeyAlgos {
+		if name, ok := registryKeyAlgos[algo]; ok {
+			encryption = name
+			break
+		}
+	}
+	if encryption == "" {
+		return chainConfig{}, fmt.Errorf("unsupported key algorithms %v", rc.KeyAlgos)
+	}
+
+	coinType := uint32(118)
+	if rc.Slip44 != nil {
+		coinType = *rc.Slip44
+	}
+
+	return chainConfig{
+		Name:       rc.ChainName,
+		Prefix:     rc.Bech32Prefix,
+		Encryption: encryption,
+		CoinType:   coinType,
+	}, nil
+}
+
+// findRegistryFiles returns the chain.json files in path. If path is a directory,
+// it is searched recursively, otherwise path itself is returned.
+func findRegistryFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && p != path && d.Name()[0] == '.' {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() && d.Name() == registryChainFile {
+			files = append(files, p)
+		}
+		return nil
+	})
+	return files, err
+}
+
+// readRegistryChains reads chain definitions from chain-registry files and directories.
+// Chains that can't be converted or validated are skipped and reported as warnings.
+func readRegistryChains(paths []string) ([]chainConfig, []string, error) {
+	var configs []chainConfig
+	var warnings []string
+	seen := make(map[string]string)
+
+	for _, path := range paths {
+		files, err := findRegistryFiles(path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			var rc registryChain
+			if err := json.Unmarshal(data, &rc); err != nil {
+				warnings = append(warnings, "skipping "+file+": "+err.Error())
+				continue
+			}
+
+			cfg, err := rc.toChainConfig()
+			if err == nil {
+				_, err = cfg.toChain()
+			}
+			if err != nil {
+				warnings = append(warnings, "skipping "+file+": "+err.Error())
+				continue
+			}
+
+			if other, ok := seen[cfg.Name]; ok {
+				warnings = append(warnings, "skipping "+file+": chain "+cfg.Name+" already imported from "+other)
+				continue
+			}
+			seen[cfg.Name] = file
+			configs = append(configs, cfg)
+		}
+	}
+
+	return configs, warnings, nil
+}
+
+// importRegistryCommand implements the import-registry subcommand. It converts chain-registry
+// chain.json files into a chains file, merging them into the output file if it already exists.
+func importRegistryCommand(args []string) error {
+	flags := pflag.NewFlagSet("import-registry", pflag.ContinueOnError)
+	var output = flags.StringP("output", "o", "", "Chains file to write, merged with its existing chains (default stdout)")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: vanity-forge import-registry [flags] <chain.json or chain-registry directory>...")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("no chain-registry files or directories given")
+	}
+
+	configs, warnings, err := readRegistryChains(flags.Args())
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, "WARNING: "+warning)
+	}
+
+	if *output == "" {
+		data, err := encodeChainsFile(chainsFile{Chains: configs}, false)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	var file chainsFile
+	if _, err := os.Stat(*output); err == nil {
+		if file, err = readChainsFile(*output); err != nil {
+			return err
+		}
+	}
+	file.Chains = mergeChainConfigs(file.Chains, configs)
+
+	if err := writeChainsFile(*output, file); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Imported %d chains into %s\n", len(configs), *output)
+	return nil
+}
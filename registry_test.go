@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeRegistryChain(t *testing.T, dir string, name string, content string) {
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, name), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, name, registryChainFile), []byte(content), 0o600))
+}
+
+func TestRegistryChain_ToChainConfig(t *testing.T) {
+	slip44 := uint32(60)
+
+	cfg, err := registryChain{ChainName: "osmosis", Bech32Prefix: "osmo"}.toChainConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, chainConfig{Name: "osmosis", Prefix: "osmo", Encryption: "secp256k1", CoinType: 118}, cfg)
+
+	cfg, err = registryChain{ChainName: "evmos", Bech32Prefix: "evmos", Slip44: &slip44, KeyAlgos: []string{"ethsecp256k1"}}.toChainConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, chainConfig{Name: "evmos", Prefix: "evmos", Encryption: "ethsecp256k1", CoinType: 60}, cfg)
+
+	_, err = registryChain{ChainName: "polkadot", Bech32Prefix: "dot", KeyAlgos: []string{"sr25519"}}.toChainConfig()
+	assert.ErrorContains(t, err, "unsupported key algorithms")
+
+	_, err = registryChain{ChainName: "noprefix"}.toChainConfig()
+	assert.ErrorContains(t, err, "bech32_prefix is missing")
+}
+
+func TestReadRegistryChains(t *testing.T) {
+	dir := t.TempDir()
+	writeRegistryChain(t, dir, "osmosis", `{"chain_name": "osmosis", "bech32_prefix": "osmo", "slip44": 118, "key_algos": ["secp256k1"]}`)
+	writeRegistryChain(t, dir, "neutron", `{"chain_name": "neutron", "bech32_prefix": "neutron", "slip44": 118}`)
+	writeRegistryChain(t, dir, "penumbra", `{"chain_name": "penumbra", "bech32_prefix": "penumbra", "key_algos": ["ed25519"]}`)
+	writeRegistryChain(t, dir, "broken", `{"chain_name": `)
+	writeRegistryChain(t, filepath.Join(dir, ".github"), "ignored", `{"chain_name": "ignored", "bech32_prefix": "ignored"}`)
+
+	configs, warnings, err := readRegistryChains([]string{dir, filepath.Join(dir, "osmosis", registryChainFile)})
+	assert.NoError(t, err)
+	assert.Equal(t, []chainConfig{
+		{Name: "neutron", Prefix: "neutron", Encryption: "secp256k1", CoinType: 118},
+		{Name: "osmosis", Prefix: "osmo", Encryption: "secp256k1", CoinType: 118},
+	}, configs)
+	assert.Len(t, warnings, 3)
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/exp/slices"
+)
+
+// Scores are the scores a scoring run can weigh:
+//   - prefix: length of the longest prefix of the search string the address starts with
+//   - repeats: number of characters equal to the character before them
+//   - zeros: number of leading zeros
+//   - words: length of the longest word of the wordlist the address contains
+//   - ratio: share of the address made of letters only or digits only, from 0.5 to 1
+var Scores = []string{"prefix", "repeats", "zeros", "words", "ratio"}
+
+// scoreMode is the matcher mode reported for results of scoring runs.
+const scoreMode = "score"
+
+// scoreWeights are the weights of the scores of a scoring run, keyed by score name.
+type scoreWeights map[string]float64
+
+// parseScoreWeights parses weights in the form "prefix=2,repeats=1".
+func parseScoreWeights(s string) (scoreWeights, error) {
+	weights := scoreWeights{}
+	for _, field := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if !ok {
+			return nil, fmt.Errorf("expected <score>=<weight>, got %q", field)
+		}
+		if !slices.Contains(Scores, name) {
+			return nil, fmt.Errorf("invalid score %q, must be one of: %v", name, Scores)
+		}
+
+		weight, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(weight) || math.IsInf(weight, 0) {
+			return nil, fmt.Errorf("invalid weight %q of score %s", value, name)
+		}
+		weights[name] = weight
+	}
+	return weights, nil
+}
+
+// String returns the weights in the order of Scores, e.g. "prefix=2 repeats=1".
+func (w scoreWeights) String() string {
+	var parts []string
+	for _, name := range Scores {
+		if weight, ok := w[name]; ok {
+			parts = append(parts, name+"="+strconv.FormatFloat(weight, 'g', -1, 64))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// scorer scores addresses by the weighted sum of their scores.
+type scorer struct {
+	weights scoreWeights
+	search  string       // search string of the prefix score
+	words   *ahoCorasick // wordlist of the words score, nil without one
+	lengths []int        // length of every word of the wordlist
+}
+
+// newScorer returns a scorer with the given weights. The prefix score needs a search string
+// and the words score a wordlist, it returns an error if they are weighted without one.
+func newScorer(weights scoreWeights, search string, words []string) (scorer, error) {
+	if weights["prefix"] != 0 && search == "" {
+		return scorer{}, fmt.Errorf("the prefix score needs a search string")
+	}
+	if weights["words"] != 0 && len(words) == 0 {
+		return scorer{}, fmt.Errorf("the words score needs a wordlist")
+	}
+
+	s := scorer{weights: weights, search: search}
+	if len(words) > 0 {
+		s.words = newAhoCorasick(words)
+		for _, word := range words {
+			s.lengths = append(s.lengths, len(word))
+		}
+	}
+	return s, nil
+}
+
+// Score returns the weighted score of an address without the chain prefix.
+func (s scorer) Score(candidate string) float64 {
+	score := 0.0
+	for _, name := range Scores {
+		if weight := s.weights[name]; weight != 0 {
+			score += weight * s.score(name, candidate)
+		}
+	}
+	return score
+}
+
+// score returns the unweighted score of the candidate with the given name.
+func (s scorer) score(name string, candidate string) float64 {
+	n := 0
+	switch name {
+	case "prefix":
+		for n < len(s.search) && n < len(candidate) && candidate[n] == s.search[n] {
+			n++
+		}
+	case "repeats":
+		for i := 1; i < len(candidate); i++ {
+			if candidate[i] == candidate[i-1] {
+				n++
+			}
+		}
+	case "zeros":
+		for n < len(candidate) && candidate[n] == '0' {
+			n++
+		}
+	case "words":
+		if s.words != nil {
+			s.words.Find(candidate, func(word int, end int) { n = max(n, s.lengths[word]) })
+		}
+	case "ratio":
+		if len(candidate) == 0 {
+			return 0
+		}
+		digits := 0
+		for _, char := range candidate {
+			if '0' <= char && char <= '9' {
+				digits++
+			}
+		}
+		return float64(max(digits, len(candidate)-digits)) / float64(len(candidate))
+	}
+	return float64(n)
+}
+
+// readWordlist reads a wordlist with one word per line. Words are lowercased,
+// empty lines and lines starting with # are skipped.
+func readWordlist(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		word := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if word != "" && !strings.HasPrefix(word, "#") {
+			words = append(words, word)
+		}
+	}
+	return words, scanner.Err()
+}
+
+// rankedWallet is a wallet on a leaderboard with its score.
+type rankedWallet struct {
+	Wallet wallet
+	Score  float64
+}
+
+// leaderboard keeps the best scoring wallets offered by the workers of a scoring run.
+// Wallets scoring no more than the lowest score of a full leaderboard are rejected
+// without taking the lock.
+type leaderboard struct {
+	size      int
+	mu        sync.Mutex
+	entries   []rankedWallet // best first, wallets with equal scores in the order they were offered
+	threshold atomic.Uint64  // bits of the lowest score once the leaderboard is full
+	full      atomic.Bool
+}
+
+// newLeaderboard returns an empty leaderboard of the given size.
+func newLeaderboard(size int) *leaderboard {
+	return &leaderboard{size: size}
+}
+
+// Offer ranks the wallet on the leaderboard if it scores higher than its lowest entry,
+// or if the leaderboard isn't full yet. Wallets already on the leaderboard are ignored.
+func (b *leaderboard) Offer(w wallet, score float64) {
+	if b.full.Load() && score <= math.Float64frombits(b.threshold.Load()) {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.entries) == b.size && score <= b.entries[len(b.entries)-1].Score {
+		return
+	}
+	for _, entry := range b.entries {
+		if entry.Wallet.Address == w.Address {
+			return
+		}
+	}
+
+	i := sort.Search(len(b.entries), func(i int) bool { return b.entries[i].Score < score })
+	b.entries = append(b.entries, rankedWallet{})
+	copy(b.entries[i+1:], b.entries[i:])
+	b.entries[i] = rankedWallet{Wallet: w, Score: score}
+
+	if len(b.entries) > b.size {
+		b.entries = b.entries[:b.size]
+	}
+	if len(b.entries) == b.size {
+		b.threshold.Store(math.Float64bits(b.entries[len(b.entries)-1].Score))
+		b.full.Store(true)
+	}
+}
+
+// Ranked returns the wallets on the leaderboard, best first.
+func (b *leaderboard) Ranked() []rankedWallet {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]rankedWallet(nil), b.entries...)
+}
+
+// Best returns the best score on the leaderboard and the number of ranked wallets.
+func (b *leaderboard) Best() (float64, int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.entries) == 0 {
+		return 0, 0
+	}
+	return b.entries[0].Score, len(b.entries)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseScoreWeights(t *testing.T) {
+	weights, err := parseScoreWeights("zeros=1, prefix=2.5")
+	assert.NoError(t, err)
+	assert.Equal(t, scoreWeights{"prefix": 2.5, "zeros": 1}, weights)
+	assert.Equal(t, "prefix=2.5 zeros=1", weights.String())
+
+	_, err = parseScoreWeights("prefix")
+	assert.ErrorContains(t, err, "expected <score>=<weight>")
+
+	_, err = parseScoreWeights("vowels=1")
+	assert.ErrorContains(t, err, `invalid score "vowels"`)
+
+	_, err = parseScoreWeights("prefix=many")
+	assert.ErrorContains(t, err, `invalid weight "many" of score prefix`)
+}
+
+func TestScorer_Score(t *testing.T) {
+	s, err := newScorer(scoreWeights{"prefix": 1, "repeats": 1, "zeros": 1, "words": 1, "ratio": 1}, "00ab", []string{"dead", "beef", "deadbeef"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3.0, s.score("prefix", "00a1"))
+	assert.Equal(t, 3.0, s.score("repeats", "aaab11"))
+	assert.Equal(t, 2.0, s.score("zeros", "00a0"))
+	assert.Equal(t, 8.0, s.score("words", "12deadbeef"))
+	assert.Equal(t, 0.0, s.score("words", "12345678"))
+	assert.Equal(t, 0.75, s.score("ratio", "a123"))
+
+	// 2 of the prefix, 1 repeat, 2 zeros, the 4 letter word dead and 4 of 8 digits
+	assert.Equal(t, 2+1+2+4+0.5, s.Score("00dead12"))
+
+	_, err = newScorer(scoreWeights{"prefix": 1}, "", nil)
+	assert.Error(t, err)
+	_, err = newScorer(scoreWeights{"words": 1}, "", nil)
+	assert.Error(t, err)
+}
+
+func TestLeaderboard(t *testing.T) {
+	board := newLeaderboard(3)
+	for i, score := range []float64{2, 5, 1, 5, 3, 0} {
+		board.Offer(wallet{Address: strconv.Itoa(i)}, score)
+	}
+	board.Offer(wallet{Address: "1"}, 5)
+
+	var addresses []string
+	var scores []float64
+	for _, entry := range board.Ranked() {
+		addresses = append(addresses, entry.Wallet.Address)
+		scores = append(scores, entry.Score)
+	}
+	assert.Equal(t, []string{"1", "3", "4"}, addresses)
+	assert.Equal(t, []float64{5, 5, 3}, scores)
+
+	best, ranked := board.Best()
+	assert.Equal(t, 5.0, best)
+	assert.Equal(t, 3, ranked)
+}
+
+func TestLeaderboard_Concurrent(t *testing.T) {
+	board := newLeaderboard(10)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				board.Offer(wallet{Address: strconv.Itoa(g*1000 + i)}, float64(g*1000+i))
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	ranked := board.Ranked()
+	assert.Len(t, ranked, 10)
+	for i, entry := range ranked {
+		assert.Equal(t, float64(3999-i), entry.Score)
+	}
+}
+
+func TestScoringPool(t *testing.T) {
+	m := matcher{Chain: AvailableChains[1]}
+	s, err := newScorer(scoreWeights{"repeats": 1}, "", nil)
+	assert.NoError(t, err)
+	board := newLeaderboard(5)
+
+	var attempts atomic.Uint64
+	pool := startScoringPool(context.Background(), m, s, board, 2, &attempts, searchBudget{MaxAttempts: 200})
+	_, err = pool.Next(context.Background())
+	assert.ErrorIs(t, err, errMaxAttempts)
+	pool.Stop()
+	assert.Equal(t, uint64(200), attempts.Load())
+
+	ranked := board.Ranked()
+	assert.Len(t, ranked, 5)
+	for i, entry := range ranked {
+		assert.Equal(t, s.Score(m.trim(entry.Wallet.Address)), entry.Score)
+		if i > 0 {
+			assert.LessOrEqual(t, entry.Score, ranked[i-1].Score)
+		}
+	}
+}
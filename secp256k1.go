@@ -1,9 +1,13 @@
 package main
 
 import (
+	"errors"
+	"strconv"
 	"strings"
 
 	"github.com/cosmos/cosmos-sdk/types/bech32"
+	secp "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/tendermint/tendermint/crypto/secp256k1"
 )
 
@@ -12,6 +16,12 @@ type secp256k1Wallet struct {
 	Chain chain
 }
 
+func init() {
+	RegisterGenerator(Secp256k1, func(c chain) Generator {
+		return secp256k1Wallet{Chain: c}
+	})
+}
+
 // bech32digits represents the digits allowed in the Bech32 alphabet.
 const bech32digits = "023456789"
 
@@ -21,6 +31,30 @@ const bech32letters = "acdefghjklmnpqrstuvwxyzACDEFGHJKLMNPQRSTUVWXYZ"
 // bech32chars represents the alphanumeric characters allowed in the Bech32 alphabet.
 const bech32chars = bech32digits + bech32letters
 
+// bech32AddressLength is the length of a bech32 encoded 20 byte address after the full prefix,
+// 32 data characters followed by a 6 character checksum.
+const bech32AddressLength = 38
+
+// Alphabet returns the characters allowed in the Bech32 alphabet.
+func (w secp256k1Wallet) Alphabet() string {
+	return bech32chars
+}
+
+// Digits returns the digits allowed in the Bech32 alphabet.
+func (w secp256k1Wallet) Digits() string {
+	return bech32digits
+}
+
+// Letters returns the letters allowed in the Bech32 alphabet.
+func (w secp256k1Wallet) Letters() string {
+	return bech32letters
+}
+
+// AddressLength returns the length of the address after the full prefix.
+func (w secp256k1Wallet) AddressLength() int {
+	return bech32AddressLength
+}
+
 // bech32Only checks if a string contains only characters from the Bech32 alphabet.
 func (w secp256k1Wallet) bech32Only(s string) bool {
 	return w.countUnionChars(s, bech32chars) == len(s)
@@ -57,17 +91,18 @@ func (w secp256k1Wallet) CheckRequiredLetters(candidate string, required int) bo
 // It returns a list of errors encountered during validation.
 func (w secp256k1Wallet) ValidateInput(SearchString string, RequiredLetters int, RequiredDigits int) []string {
 	var errs []string
+	maxLength := w.AddressLength()
 	if !w.bech32Only(SearchString) {
 		errs = append(errs, "ERROR: "+SearchString+" contains bech32 incompatible characters.")
 	}
-	if len(SearchString) > 38 {
-		errs = append(errs, "ERROR: "+SearchString+" is too long. Must be max 38 characters.")
+	if len(SearchString) > maxLength {
+		errs = append(errs, "ERROR: "+SearchString+" is too long. Must be max "+strconv.Itoa(maxLength)+" characters.")
 	}
 	if RequiredDigits < 0 || RequiredLetters < 0 {
 		errs = append(errs, "ERROR: Can't require negative amount of characters.")
 	}
-	if RequiredDigits+RequiredLetters > 38 {
-		errs = append(errs, "ERROR: Can't require more than 38 characters.")
+	if RequiredDigits+RequiredLetters > maxLength {
+		errs = append(errs, "ERROR: Can't require more than "+strconv.Itoa(maxLength)+" characters.")
 	}
 
 	return errs
@@ -75,12 +110,48 @@ func (w secp256k1Wallet) ValidateInput(SearchString string, RequiredLetters int,
 
 // GenerateWallet generates a new secp256k1 wallet.
 func (w secp256k1Wallet) GenerateWallet() wallet {
-	var privkey secp256k1.PrivKey = secp256k1.GenPrivKey()
-	var pubkey secp256k1.PubKey = privkey.PubKey().(secp256k1.PubKey)
-	bech32Addr, err := bech32.ConvertAndEncode(w.Chain.Prefix, pubkey.Address())
+	generated, err := w.WalletFromPrivateKey(secp256k1.GenPrivKey())
 	if err != nil {
 		panic(err)
 	}
 
-	return wallet{bech32Addr, pubkey, privkey}
+	return generated
+}
+
+// WalletFromPrivateKey derives the secp256k1 wallet of a private key.
+// The key is checked to be in range without deriving its public key twice.
+func (w secp256k1Wallet) WalletFromPrivateKey(privateKey []byte) (wallet, error) {
+	var scalar secp.ModNScalar
+	if len(privateKey) != 32 || scalar.SetByteSlice(privateKey) || scalar.IsZero() {
+		return wallet{}, errors.New("invalid private key")
+	}
+
+	var privkey secp256k1.PrivKey = privateKey
+	derived, err := w.walletFromPubKey(privkey.PubKey().(secp256k1.PubKey))
+	if err != nil {
+		return wallet{}, err
+	}
+
+	derived.PrivateKey = privkey
+	return derived, nil
+}
+
+// WalletFromPublicKey derives the secp256k1 wallet of a public key.
+func (w secp256k1Wallet) WalletFromPublicKey(publicKey []byte) (wallet, error) {
+	pub, err := parsePublicKey(publicKey)
+	if err != nil {
+		return wallet{}, err
+	}
+
+	return w.walletFromPubKey(crypto.CompressPubkey(pub))
+}
+
+// walletFromPubKey derives the bech32 address of a compressed public key.
+func (w secp256k1Wallet) walletFromPubKey(pubkey secp256k1.PubKey) (wallet, error) {
+	bech32Addr, err := bech32.ConvertAndEncode(w.Chain.Prefix, pubkey.Address())
+	if err != nil {
+		return wallet{}, err
+	}
+
+	return wallet{Address: bech32Addr, PublicKey: pubkey}, nil
 }
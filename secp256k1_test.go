@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -57,3 +58,19 @@ func TestSecp256k1Wallet_GenerateWallet(t *testing.T) {
 	assert.NotNil(t, w.PublicKey)
 	assert.NotNil(t, w.PrivateKey)
 }
+
+func TestSecp256k1Wallet_WalletFromPrivateKey(t *testing.T) {
+	g := secp256k1Wallet{Chain: AvailableChains[1]}
+	generated := g.GenerateWallet()
+
+	w, err := g.WalletFromPrivateKey(generated.PrivateKey)
+	assert.NoError(t, err)
+	assert.Equal(t, generated.Address, w.Address)
+
+	_, err = g.WalletFromPrivateKey(make([]byte, 32))
+	assert.ErrorContains(t, err, "invalid private key")
+	_, err = g.WalletFromPrivateKey(bytes.Repeat([]byte{0xff}, 32))
+	assert.ErrorContains(t, err, "invalid private key")
+	_, err = g.WalletFromPrivateKey(generated.PrivateKey[:31])
+	assert.ErrorContains(t, err, "invalid private key")
+}
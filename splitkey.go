@@ -0,0 +1,191 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	secp "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/spf13/pflag"
+)
+
+// Split-key vanity generation lets an untrusted machine search for a vanity address without
+// learning its private key. The requester keeps a secret key a and publishes its public key A.
+// The searcher looks for a partial key b where the address of A + b*G matches, and the requester
+// combines the keys locally into the private key a + b of the found address.
+
+// splitKeyGenerator wraps a Generator to search partial private keys for a split-key request.
+type splitKeyGenerator struct {
+	Generator
+	base secp.JacobianPoint // public key of the requester's secret key
+}
+
+// newSplitKeyGenerator returns a splitKeyGenerator for the requester's public key.
+// It returns an error if publicKey is not a valid secp256k1 public key.
+func newSplitKeyGenerator(g Generator, publicKey []byte) (splitKeyGenerator, error) {
+	pub, err := secp.ParsePubKey(publicKey)
+	if err != nil {
+		return splitKeyGenerator{}, fmt.Errorf("invalid split-key public key: %w", err)
+	}
+
+	var base secp.JacobianPoint
+	pub.AsJacobian(&base)
+
+	return splitKeyGenerator{Generator: g, base: base}, nil
+}
+
+// GenerateWallet generates a random partial private key and derives the wallet of the combined
+// public key. The private key of the returned wallet is the partial key.
+func (g splitKeyGenerator) GenerateWallet() wallet {
+	partial, err := secp.GeneratePrivateKey()
+	if err != nil {
+		panic(err)
+	}
+
+	var point, sum secp.JacobianPoint
+	secp.ScalarBaseMultNonConst(&partial.Key, &point)
+	secp.AddNonConst(&g.base, &point, &sum)
+	sum.ToAffine()
+
+	derived, err := g.Generator.WalletFromPublicKey(secp.NewPublicKey(&sum.X, &sum.Y).SerializeCompressed())
+	if err != nil {
+		panic(err)
+	}
+
+	derived.PrivateKey = partial.Serialize()
+	derived.PartialKey = true
+
+	return derived
+}
+
+// combineKeys adds the secret and partial private keys modulo the curve order.
+// It returns an error if a key is invalid or the combined key is zero.
+func combineKeys(secret []byte, partial []byte) ([]byte, error) {
+	var a, b secp.ModNScalar
+	if len(secret) != 32 || a.SetByteSlice(secret) || a.IsZero() {
+		return nil, errors.New("invalid secret key")
+	}
+	if len(partial) != 32 || b.SetByteSlice(partial) || b.IsZero() {
+		return nil, errors.New("invalid partial key")
+	}
+
+	a.Add(&b)
+	if a.IsZero() {
+		return nil, errors.New("combined key is zero")
+	}
+
+	combined := a.Bytes()
+	return combined[:], nil
+}
+
+// readHexKey reads a hex encoded key from a file, ignoring surrounding whitespace.
+func readHexKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return hex.DecodeString(strings.TrimSpace(string(data)))
+}
+
+// splitKeygenCommand implements the split-keygen subcommand. It writes a new secret key to a file
+// and prints the public key to hand out to the searcher.
+func splitKeygenCommand(args []string) error {
+	flags := pflag.NewFlagSet("split-keygen", pflag.ContinueOnError)
+	var secretFile = flags.String("secret-file", "", "File to write the secret key to, must not exist (required)")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: vanity-forge split-keygen --secret-file <file>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *secretFile == "" {
+		flags.Usage()
+		return errors.New("--secret-file is required")
+	}
+
+	secret, err := secp.GeneratePrivateKey()
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(*secretFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.WriteString(hex.EncodeToString(secret.Serialize()) + "\n"); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(os.Stderr, "Secret key written to "+*secretFile+", keep it private. Hand out the public key:")
+	fmt.Println(hex.EncodeToString(secret.PubKey().SerializeCompressed()))
+	return nil
+}
+
+// combineCommand implements the combine subcommand. It combines the secret key of a split-key
+// request with a partial key found by the searcher and prints the resulting wallet.
+func combineCommand(args []string) error {
+	flags := pflag.NewFlagSet("combine", pflag.ContinueOnError)
+	var chainflag = flags.StringP("chain", "c", "", "Chain selector string (required)")
+	var chainsFile = flags.String("chains-file", "", "YAML or JSON file with extra chain definitions")
+	var secretFile = flags.String("secret-file", "", "File with the secret key from split-keygen (required)")
+	var partialKey = flags.String("partial-key", "", "Partial private key found by the search (required)")
+	var address = flags.String("address", "", "Expected address, checked against the combined key")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: vanity-forge combine -c <chain> --secret-file <file> --partial-key <hex>")
+		flags.PrintDefaults()
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *chainflag == "" || *secretFile == "" || *partialKey == "" {
+		flags.Usage()
+		return errors.New("--chain, --secret-file and --partial-key are required")
+	}
+
+	if err := loadAvailableChains(*chainsFile); err != nil {
+		return fmt.Errorf("Invalid chains file %w", err)
+	}
+	selectedChain, err := findChain(*chainflag)
+	if err != nil {
+		return err
+	}
+	g, err := NewGenerator(selectedChain)
+	if err != nil {
+		return err
+	}
+
+	secret, err := readHexKey(*secretFile)
+	if err != nil {
+		return fmt.Errorf("can't read secret key: %w", err)
+	}
+	partial, err := hex.DecodeString(strings.TrimSpace(*partialKey))
+	if err != nil {
+		return fmt.Errorf("invalid partial key: %w", err)
+	}
+
+	combined, err := combineKeys(secret, partial)
+	if err != nil {
+		return err
+	}
+	combinedWallet, err := g.WalletFromPrivateKey(combined)
+	if err != nil {
+		return err
+	}
+
+	if *address != "" && !strings.EqualFold(*address, combinedWallet.Address) {
+		return fmt.Errorf("combined address %s doesn't match %s, check the secret key and partial key", combinedWallet.Address, *address)
+	}
+
+	fmt.Println(combinedWallet)
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+
+	secp "github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitKeyGenerator_Combine(t *testing.T) {
+	chains := []chain{
+		AvailableChains[1],
+		AvailableChains[3],
+		{Name: "evmos", Prefix: "evmos", PrefixFull: "evmos1", CoinType: 60, Encryption: Ethsecp256k1},
+	}
+
+	for _, c := range chains {
+		g, err := NewGenerator(c)
+		assert.NoError(t, err)
+
+		secret, err := secp.GeneratePrivateKey()
+		assert.NoError(t, err)
+
+		splitGen, err := newSplitKeyGenerator(g, secret.PubKey().SerializeCompressed())
+		assert.NoError(t, err)
+
+		found := splitGen.GenerateWallet()
+		assert.True(t, found.PartialKey, c.Name)
+
+		combined, err := combineKeys(secret.Serialize(), found.PrivateKey)
+		assert.NoError(t, err)
+
+		combinedWallet, err := g.WalletFromPrivateKey(combined)
+		assert.NoError(t, err)
+		assert.Equal(t, found.Address, combinedWallet.Address, c.Name)
+		assert.Equal(t, found.PublicKey, combinedWallet.PublicKey, c.Name)
+		assert.Equal(t, found.HexAddress, combinedWallet.HexAddress, c.Name)
+	}
+}
+
+func TestNewSplitKeyGenerator_Invalid(t *testing.T) {
+	_, err := newSplitKeyGenerator(secp256k1Wallet{}, []byte{2, 1, 2, 3})
+	assert.ErrorContains(t, err, "invalid split-key public key")
+}
+
+func TestCombineKeys_Invalid(t *testing.T) {
+	key := make([]byte, 32)
+	key[31] = 1
+
+	_, err := combineKeys(make([]byte, 32), key)
+	assert.ErrorContains(t, err, "invalid secret key")
+
+	_, err = combineKeys(key, key[:16])
+	assert.ErrorContains(t, err, "invalid partial key")
+
+	// n - 1 + 1 = 0 mod n
+	var minusOne secp.ModNScalar
+	minusOne.SetInt(1).Negate()
+	minusOneBytes := minusOne.Bytes()
+	_, err = combineKeys(minusOneBytes[:], key)
+	assert.ErrorContains(t, err, "combined key is zero")
+}
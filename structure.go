@@ -0,0 +1,207 @@
+package main
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// StructureModes are the matcher modes searching for shapes of the address instead of literal strings.
+// Their search string is the minimum score, e.g. "6" for a run of six identical characters:
+//   - runs: identical characters in a row, e.g. 777777
+//   - repeats: a block of characters repeated right after itself, e.g. abcabc
+//   - palindromes: a palindrome the address starts or ends with, e.g. 4a7a4
+//   - sequences: characters ascending one by one, e.g. 3456 or cdef
+var StructureModes = []string{"runs", "repeats", "palindromes", "sequences"}
+
+// structureModeUnit returns the unit of the score of a structure mode.
+func structureModeUnit(mode string) string {
+	switch mode {
+	case "repeats":
+		return "characters in the repeated block"
+	case "palindromes":
+		return "characters in the palindrome"
+	case "sequences":
+		return "characters in the ascending sequence"
+	default:
+		return "identical characters in a row"
+	}
+}
+
+// structureScore returns the score of an address without the chain prefix in a structure mode:
+// the length of its longest run, repeated block, palindromic prefix or suffix, or ascending sequence.
+func structureScore(mode string, candidate string) int {
+	switch mode {
+	case "repeats":
+		for k := len(candidate) / 2; k > 0; k-- {
+			for i := 0; i+2*k <= len(candidate); i++ {
+				if candidate[i:i+k] == candidate[i+k:i+2*k] {
+					return k
+				}
+			}
+		}
+		return 0
+	case "palindromes":
+		for k := len(candidate); k > 0; k-- {
+			if isPalindrome(candidate[:k]) || isPalindrome(candidate[len(candidate)-k:]) {
+				return k
+			}
+		}
+		return 0
+	}
+
+	// Runs and sequences are chains of characters following the character before them
+	step := byte(0)
+	if mode == "sequences" {
+		step = 1
+	}
+
+	best, n := 0, 0
+	for i := 0; i < len(candidate); i++ {
+		if i > 0 && candidate[i] == candidate[i-1]+step {
+			n++
+		} else {
+			n = 1
+		}
+		best = max(best, n)
+	}
+	return best
+}
+
+// isPalindrome reports whether s reads the same backwards.
+func isPalindrome(s string) bool {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		if s[i] != s[j] {
+			return false
+		}
+	}
+	return true
+}
+
+// compileStructure compiles a structure mode with a minimum score into a predicate.
+func compileStructure(mode string, minScore int) predicate {
+	return func(candidate string) bool {
+		return structureScore(mode, candidate) >= minScore
+	}
+}
+
+// structureModeMax returns the highest score of a structure mode for addresses of the given length
+// over the alphabet. Sequences can't be longer than the longest ascending sequence of the alphabet.
+func structureModeMax(mode string, alphabet string, length int) int {
+	switch mode {
+	case "repeats":
+		return length / 2
+	case "sequences":
+		return min(structureScore(mode, sortedChars(alphabet)), length)
+	default:
+		return length
+	}
+}
+
+// validateStructure checks that the search string of a structure mode is a score between the lowest
+// score that is a shape at all and the highest score of addresses of the given length over the alphabet.
+// It returns a slice of validation error messages.
+func validateStructure(mode string, search string, alphabet string, length int) []string {
+	minScore := 2
+	if mode == "repeats" {
+		minScore = 1
+	}
+	maxScore := structureModeMax(mode, alphabet, length)
+
+	score, err := strconv.Atoi(search)
+	if err != nil || score < minScore || score > maxScore {
+		return []string{"ERROR: The search string of " + mode + " must be the number of " + structureModeUnit(mode) +
+			", between " + strconv.Itoa(minScore) + " and " + strconv.Itoa(maxScore) + "."}
+	}
+	return nil
+}
+
+// sortedChars returns the distinct characters of the alphabet in ascending order.
+func sortedChars(alphabet string) string {
+	var chars strings.Builder
+	for c := 0; c < 256; c++ {
+		if strings.IndexByte(alphabet, byte(c)) >= 0 {
+			chars.WriteByte(byte(c))
+		}
+	}
+	return chars.String()
+}
+
+// structureProbability returns the probability that an address of the given length over the
+// alphabet scores at least minScore in a structure mode. Runs and sequences are computed exactly.
+// Repeats and palindromes treat the blocks and palindromes of different lengths and positions as
+// independent, which slightly overestimates the probability of short ones.
+func structureProbability(mode string, minScore int, alphabet string, length int) float64 {
+	chars := sortedChars(alphabet)
+	size := float64(len(chars))
+
+	switch mode {
+	case "repeats":
+		miss := 0.0
+		for k := minScore; 2*k <= length; k++ {
+			miss += float64(length-2*k+1) * math.Log1p(-math.Pow(size, -float64(k)))
+		}
+		return -math.Expm1(miss)
+	case "palindromes":
+		miss := 0.0
+		for k := minScore; k <= length; k++ {
+			miss += math.Log1p(-math.Pow(size, -float64(k/2)))
+		}
+		// The address can start or end with the palindrome
+		return -math.Expm1(2 * miss)
+	case "sequences":
+		return chainProbability(chars, length, minScore, 1)
+	default:
+		return chainProbability(chars, length, minScore, 0)
+	}
+}
+
+// chainProbability returns the probability that a string of the given length over the sorted
+// characters contains a chain of at least n characters, each of them step more than the one before.
+// It tracks the probability of every last character and chain length of strings without such a chain,
+// and sums the probability of strings leaving them with their first chain of n characters.
+func chainProbability(chars string, length int, n int, step byte) float64 {
+	if n <= 1 {
+		return 1
+	}
+
+	size := len(chars)
+	next := make([]int, size) // index of the character following each character in a chain, -1 if none
+	for i := range chars {
+		next[i] = strings.IndexByte(chars, chars[i]+step)
+	}
+
+	// p[c][k] is the probability of ending with character c in a chain of k+1 characters
+	p := make([][]float64, size)
+	for c := range p {
+		p[c] = make([]float64, n-1)
+		p[c][0] = 1 / float64(size)
+	}
+
+	found := 0.0
+	for i := 1; i < length; i++ {
+		q := make([][]float64, size)
+		for c := range q {
+			q[c] = make([]float64, n-1)
+		}
+
+		for c := range p {
+			for k, pk := range p[c] {
+				// Every character but the next one of the chain starts a new chain
+				for d := range q {
+					if d != next[c] {
+						q[d][0] += pk / float64(size)
+					}
+				}
+				// Chains reaching n characters are found and leave the distribution
+				if next[c] >= 0 && k+1 < n-1 {
+					q[next[c]][k+1] += pk / float64(size)
+				} else if next[c] >= 0 {
+					found += pk / float64(size)
+				}
+			}
+		}
+		p = q
+	}
+	return found
+}
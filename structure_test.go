@@ -0,0 +1,90 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStructureScore(t *testing.T) {
+	assert.Equal(t, 4, structureScore("runs", "ab7777c77"))
+	assert.Equal(t, 3, structureScore("repeats", "xabcabcy"))
+	assert.Equal(t, 2, structureScore("repeats", "aababx"))
+	assert.Equal(t, 0, structureScore("repeats", "abc"))
+	assert.Equal(t, 5, structureScore("palindromes", "4a7a4bc"))
+	assert.Equal(t, 3, structureScore("palindromes", "xyzcdc"))
+	assert.Equal(t, 4, structureScore("sequences", "x3456a9bcd"))
+	assert.Equal(t, 1, structureScore("sequences", "9a"))
+}
+
+// countChains returns the number of strings of the given length over the characters
+// with a chain of at least n characters.
+func countChains(chars string, length int, n int, mode string) int {
+	if length == 0 {
+		return 0
+	}
+	found := 0
+	var walk func(s string)
+	walk = func(s string) {
+		if len(s) == length {
+			if structureScore(mode, s) >= n {
+				found++
+			}
+			return
+		}
+		for i := range chars {
+			walk(s + chars[i:i+1])
+		}
+	}
+	walk("")
+	return found
+}
+
+func TestChainProbability(t *testing.T) {
+	chars := "0123"
+	total := 4.0 * 4 * 4 * 4 * 4 * 4
+
+	assert.InDelta(t, float64(countChains(chars, 6, 3, "runs"))/total, chainProbability(chars, 6, 3, 0), 1e-12)
+	assert.InDelta(t, float64(countChains(chars, 6, 3, "sequences"))/total, chainProbability(chars, 6, 3, 1), 1e-12)
+	assert.Equal(t, 0.0, chainProbability(chars, 6, 5, 1))
+}
+
+func TestMatcher_StructureModes(t *testing.T) {
+	berachain := AvailableChains[3]
+	w := wallet{Address: "0x1eC365129e70f5FB256788964Ed05A31CD389ebe"}
+
+	m := matcher{Mode: "sequences", SearchString: "4", Chain: berachain}
+	assert.Empty(t, m.ValidateInput())
+	assert.True(t, m.Match(w.Address))
+	assert.Equal(t, 4, m.Score(w))
+
+	m.SearchString = "5"
+	assert.False(t, m.Match(w.Address))
+	assert.Equal(t, 4, m.Closeness(w.Address))
+	assert.Equal(t, 5, m.searchLength())
+
+	runs := matcher{Mode: "runs", SearchString: "2", Chain: berachain}
+	assert.True(t, runs.Match(w.Address))
+	assert.Equal(t, 2, runs.Score(w))
+
+	// A run of 3 in 40 hex characters starts at one of 38 positions with probability 1/256,
+	// overlapping runs make it less likely than their sum
+	d, err := matcher{Mode: "runs", SearchString: "3", Chain: berachain}.Difficulty()
+	assert.NoError(t, err)
+	assert.Less(t, d.Probability, 38.0/256)
+	assert.Greater(t, d.Probability, 0.12)
+
+	for _, mode := range StructureModes {
+		d, err := matcher{Mode: mode, SearchString: "4", Chain: AvailableChains[0]}.Difficulty()
+		assert.NoError(t, err)
+		assert.Greater(t, d.Probability, 0.0)
+		assert.Less(t, d.Probability, 0.01)
+	}
+
+	assert.Equal(t, []string{"ERROR: The search string of sequences must be the number of characters in the ascending sequence, between 2 and 10."},
+		matcher{Mode: "sequences", SearchString: "11", Chain: berachain}.ValidateInput())
+	assert.Equal(t, []string{"ERROR: The search string of repeats must be the number of characters in the repeated block, between 1 and 20."},
+		matcher{Mode: "repeats", SearchString: "x", Chain: berachain}.ValidateInput())
+	assert.Equal(t, []string{"ERROR: runs can't be combined with checksum case matching."},
+		matcher{Mode: "runs", SearchString: "4", Chain: berachain, CaseSensitive: true}.ValidateInput())
+}
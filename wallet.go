@@ -3,13 +3,41 @@ package main
 import "encoding/hex"
 
 type wallet struct {
-	Address    string
-	PublicKey  []byte
-	PrivateKey []byte
+	Address      string
+	PublicKey    []byte
+	PrivateKey   []byte
+	HexAddress   string // 0x form of the address for EVM compatible keys, if it differs from Address
+	AddressBytes []byte // raw 20 byte address of EVM wallets, scored by the zero modes
+	Mnemonic     string // BIP39 mnemonic the private key was derived from, if any
+	HDPath       string // BIP44 derivation path of the private key, if derived from a mnemonic
+	PartialKey   bool   // PrivateKey is a split-key partial key, see combineCommand
 }
 
 func (w wallet) String() string {
-	return "Private key:\t" + hex.EncodeToString(w.PrivateKey) + "\n" +
-		"Public key:\t" + hex.EncodeToString(w.PublicKey) + "\n" +
+	privateKeyLabel := "Private key:\t"
+	if w.PartialKey {
+		privateKeyLabel = "Partial key:\t"
+	}
+
+	// The private key is left out of wallets whose key was only written encrypted
+	var s string
+	if len(w.PrivateKey) > 0 {
+		s = privateKeyLabel + hex.EncodeToString(w.PrivateKey) + "\n"
+	}
+	s += "Public key:\t" + hex.EncodeToString(w.PublicKey) + "\n" +
 		"Address:\t" + w.Address
+
+	if w.HexAddress != "" {
+		s += "\n" + "Hex address:\t" + w.HexAddress
+	}
+
+	if w.Mnemonic != "" {
+		s += "\n" + "Mnemonic:\t" + w.Mnemonic
+	}
+
+	if w.HDPath != "" {
+		s += "\n" + "HD path:\t" + w.HDPath
+	}
+
+	return s
 }
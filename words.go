@@ -0,0 +1,114 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// wordsMode is the matcher mode matching any word of a wordlist. Its search string is
+// the minimum length of the words, e.g. "4".
+const wordsMode = "words"
+
+// leetspeak are the substitutions tried, in order, for letters that can't appear in addresses.
+var leetspeak = map[rune]string{
+	'a': "4",
+	'b': "86",
+	'e': "3",
+	'g': "96",
+	'i': "1l",
+	'l': "1",
+	'o': "0",
+	's': "5",
+	't': "7",
+	'z': "2",
+}
+
+// wordMatch is a word of the wordlist found in an address.
+type wordMatch struct {
+	Word   string // word of the wordlist
+	Form   string // word as it appears in the address, with leetspeak substitutions
+	Offset int    // index of the word in the address after the prefix
+}
+
+// String returns the word and where it was found, e.g. "boot as b007 at offset 12".
+func (w wordMatch) String() string {
+	s := w.Word
+	if w.Form != w.Word {
+		s += " as " + w.Form
+	}
+	return s + " at offset " + strconv.Itoa(w.Offset)
+}
+
+// wordSet is a wordlist filtered to the words that can appear in addresses, found in a single
+// pass over an address by an Aho-Corasick automaton.
+type wordSet struct {
+	words     []string // words of the wordlist
+	forms     []string // words as they appear in addresses
+	automaton *ahoCorasick
+}
+
+// newWordSet returns the words of the wordlist of minLength to maxLength characters, that consist
+// of characters of the alphabet. With leet, letters outside of the alphabet are replaced by their
+// first leetspeak substitution in the alphabet. Duplicate forms are kept once.
+func newWordSet(words []string, alphabet string, minLength int, maxLength int, leet bool) *wordSet {
+	set := &wordSet{}
+	seen := make(map[string]bool)
+
+	for _, word := range words {
+		form, ok := wordForm(word, alphabet, leet)
+		if !ok || len(form) < minLength || len(form) > maxLength || seen[form] {
+			continue
+		}
+		seen[form] = true
+		set.words = append(set.words, word)
+		set.forms = append(set.forms, form)
+	}
+
+	if len(set.forms) > 0 {
+		set.automaton = newAhoCorasick(set.forms)
+	}
+	return set
+}
+
+// wordForm returns the word as it can appear in addresses of the alphabet, or false if it can't.
+func wordForm(word string, alphabet string, leet bool) (string, bool) {
+	var form strings.Builder
+	for _, char := range word {
+		if strings.ContainsRune(alphabet, char) {
+			form.WriteRune(char)
+			continue
+		}
+		if !leet {
+			return "", false
+		}
+
+		i := strings.IndexAny(leetspeak[char], alphabet)
+		if i < 0 {
+			return "", false
+		}
+		form.WriteByte(leetspeak[char][i])
+	}
+	return form.String(), true
+}
+
+// Len returns the number of words in the set.
+func (s *wordSet) Len() int {
+	return len(s.forms)
+}
+
+// Find returns the longest word found in the candidate, the first one of several equally long
+// words. It returns false if the candidate contains no word.
+func (s *wordSet) Find(candidate string) (wordMatch, bool) {
+	var match wordMatch
+	found := false
+
+	if s.automaton != nil {
+		s.automaton.Find(candidate, func(word int, end int) {
+			if form := s.forms[word]; !found || len(form) > len(match.Form) {
+				match = wordMatch{Word: s.words[word], Form: form, Offset: end - len(form)}
+				found = true
+			}
+		})
+	}
+	return match, found
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWordSet(t *testing.T) {
+	words := []string{"boot", "cafe", "zoo", "hello", "dead", "b007"}
+
+	hex := newWordSet(words, "0123456789abcdef", 4, 40, false)
+	assert.Equal(t, []string{"cafe", "dead", "b007"}, hex.forms)
+
+	leet := newWordSet(words, "0123456789abcdef", 4, 40, true)
+	assert.Equal(t, []string{"boot", "cafe", "dead"}, leet.words)
+	assert.Equal(t, []string{"b007", "cafe", "dead"}, leet.forms)
+
+	// Bech32 has no b, i, o and 1, so i becomes l instead of 1
+	form, ok := wordForm("mint", "023456789acdefghjklmnpqrstuvwxyz", true)
+	assert.True(t, ok)
+	assert.Equal(t, "mlnt", form)
+
+	_, ok = wordForm("hello", "0123456789abcdef", true)
+	assert.False(t, ok)
+}
+
+func TestWordSet_Find(t *testing.T) {
+	set := newWordSet([]string{"cafe", "boot", "cafebabe"}, "0123456789abcdef", 4, 40, true)
+
+	match, ok := set.Find("12cafebabe00b007")
+	assert.True(t, ok)
+	assert.Equal(t, wordMatch{Word: "cafebabe", Form: "cafebabe", Offset: 2}, match)
+
+	match, ok = set.Find("1b007cafe")
+	assert.True(t, ok)
+	assert.Equal(t, wordMatch{Word: "boot", Form: "b007", Offset: 1}, match)
+	assert.Equal(t, "boot as b007 at offset 1", match.String())
+
+	_, ok = set.Find("123456")
+	assert.False(t, ok)
+}
+
+func TestMatcher_Words(t *testing.T) {
+	berachain := AvailableChains[3]
+	m := matcher{Mode: wordsMode, SearchString: "4", Chain: berachain, Words: []string{"boot", "food"}, Leetspeak: true}
+
+	assert.Empty(t, m.ValidateInput())
+	assert.True(t, m.Match("0x12F00D34"))
+	assert.False(t, m.Match("0x12345678"))
+
+	word, ok := m.FindWord("0x12F00D34")
+	assert.True(t, ok)
+	assert.Equal(t, wordMatch{Word: "food", Form: "f00d", Offset: 2}, word)
+
+	// Words searches report no near-miss
+	assert.Equal(t, 0, m.Closeness("0x12F00D34"))
+	assert.Equal(t, 4, m.searchLength())
+
+	d, err := m.Difficulty()
+	assert.NoError(t, err)
+	assert.Greater(t, d.Probability, 0.0)
+
+	m.Leetspeak = false
+	assert.Equal(t, []string{"ERROR: No word of the wordlist with at least 4 characters can appear in addresses of this chain."}, m.ValidateInput())
+
+	m.Words = nil
+	assert.Equal(t, []string{"ERROR: The words mode needs a wordlist."}, m.ValidateInput())
+
+	m.SearchString = "four"
+	assert.Equal(t, []string{"ERROR: The search string of words must be the minimum word length, between 1 and 40."}, m.ValidateInput())
+}
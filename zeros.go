@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/hex"
+	"strconv"
+)
+
+// ZeroModes are the matcher modes scoring the zeros of the raw bytes of EVM addresses.
+// Their search string is the minimum score, e.g. "4" for four leading zero bytes.
+var ZeroModes = []string{"leading-zero-bytes", "zero-nibbles"}
+
+// zeroModeMax returns the highest score of a zero mode for addresses of the given number of bytes.
+func zeroModeMax(mode string, bytes int) int {
+	if mode == "zero-nibbles" {
+		return bytes * 2
+	}
+	return bytes
+}
+
+// zeroModeUnit returns the unit of the score of a zero mode.
+func zeroModeUnit(mode string) string {
+	if mode == "zero-nibbles" {
+		return "zero nibbles"
+	}
+	return "leading zero bytes"
+}
+
+// zeroScore returns the score of the raw address bytes in a zero mode: the number of leading
+// zero bytes, or the number of zero nibbles anywhere in the address.
+func zeroScore(mode string, address []byte) int {
+	n := 0
+	if mode == "zero-nibbles" {
+		for _, b := range address {
+			if b>>4 == 0 {
+				n++
+			}
+			if b&0x0f == 0 {
+				n++
+			}
+		}
+		return n
+	}
+
+	for n < len(address) && address[n] == 0 {
+		n++
+	}
+	return n
+}
+
+// compileZeros compiles a zero mode with a minimum score into a predicate. The predicate decodes
+// the hex address body, candidates of generated wallets are scored on their raw bytes by MatchWallet.
+func compileZeros(mode string, minScore int) predicate {
+	return func(candidate string) bool {
+		address, err := hex.DecodeString(candidate)
+		return err == nil && zeroScore(mode, address) >= minScore
+	}
+}
+
+// validateZeros checks that the search string of a zero mode is a score between 1 and the highest
+// score of addresses of the given number of hex characters. It returns a slice of validation error messages.
+func validateZeros(mode string, search string, length int) []string {
+	maxScore := zeroModeMax(mode, length/2)
+
+	score, err := strconv.Atoi(search)
+	if err != nil || score < 1 || score > maxScore {
+		return []string{"ERROR: The search string of " + mode + " must be the number of " + zeroModeUnit(mode) +
+			", between 1 and " + strconv.Itoa(maxScore) + "."}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZeroScore(t *testing.T) {
+	address := []byte{0x00, 0x00, 0x0f, 0xf0, 0x12}
+
+	assert.Equal(t, 2, zeroScore("leading-zero-bytes", address))
+	assert.Equal(t, 6, zeroScore("zero-nibbles", address))
+	assert.Equal(t, 0, zeroScore("leading-zero-bytes", []byte{0x01, 0x00}))
+}
+
+func TestMatcher_ZeroModes(t *testing.T) {
+	berachain := AvailableChains[3]
+	w := wallet{
+		Address:      "0x0000378772F321E1B39a9BaE419B900b6084F3ac",
+		AddressBytes: []byte{0x00, 0x00, 0x37, 0x87, 0x72, 0xf3, 0x21, 0xe1, 0xb3, 0x9a, 0x9b, 0xae, 0x41, 0x9b, 0x90, 0x0b, 0x60, 0x84, 0xf3, 0xac},
+	}
+
+	m := matcher{Mode: "leading-zero-bytes", SearchString: "2", Chain: berachain}
+	assert.Empty(t, m.ValidateInput())
+	assert.Equal(t, []int{0}, m.MatchWallet(w))
+	assert.True(t, m.Match(w.Address))
+	assert.Equal(t, 2, m.Score(w))
+
+	m.SearchString = "3"
+	assert.Nil(t, m.MatchWallet(w))
+	assert.False(t, m.Match(w.Address))
+	assert.Equal(t, 2, m.Closeness(w.Address))
+	assert.Equal(t, 3, m.searchLength())
+
+	nibbles := matcher{Mode: "zero-nibbles", SearchString: "7", Chain: berachain}
+	assert.Equal(t, 7, nibbles.Score(w))
+	assert.Equal(t, []int{0}, nibbles.MatchWallet(w))
+
+	// The mask is checked on the hex address after the score of the raw bytes
+	masked := matcher{Mode: "leading-zero-bytes", SearchString: "2", Chain: berachain, Mask: mask{Tail: "aa"}}
+	assert.Empty(t, masked.ValidateInput())
+	assert.Equal(t, []int{0}, masked.MatchWallet(w))
+	masked.Mask = mask{Tail: "dd"}
+	assert.Nil(t, masked.MatchWallet(w))
+	assert.False(t, masked.Match(w.Address))
+	masked.Mask = mask{}
+	masked.Constraints = []countConstraint{{Class: "letters", Op: "<=", Count: 5}}
+	assert.Nil(t, masked.MatchWallet(w))
+
+	d, err := m.Difficulty()
+	assert.NoError(t, err)
+	assert.InDelta(t, math.Pow(256, 3), d.ExpectedAttempts(), 1e-6)
+
+	assert.Equal(t, []string{"ERROR: leading-zero-bytes is only supported for EVM chains with 0x addresses."},
+		matcher{Mode: "leading-zero-bytes", SearchString: "2", Chain: AvailableChains[1]}.ValidateInput())
+	assert.Equal(t, []string{"ERROR: The search string of zero-nibbles must be the number of zero nibbles, between 1 and 40."},
+		matcher{Mode: "zero-nibbles", SearchString: "41", Chain: berachain}.ValidateInput())
+	assert.NotEmpty(t, matcher{Mode: "leading-zero-bytes", SearchString: "2", Chain: berachain, RequiredDigits: 1}.ValidateInput())
+}
+
+func TestEcsdaWallet_AddressBytes(t *testing.T) {
+	w := ecsdaWallet{}.GenerateWallet()
+
+	assert.Len(t, w.AddressBytes, 20)
+	assert.Equal(t, zeroScore("zero-nibbles", w.AddressBytes), matcher{Mode: "zero-nibbles", SearchString: "1", Chain: AvailableChains[3]}.Score(wallet{Address: w.Address}))
+}